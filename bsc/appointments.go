@@ -0,0 +1,165 @@
+package bsc
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+var enrollmentDatesPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_APPT.GBL" +
+	"?Page=SSR_SSENRL_APPT"
+
+// An EnrollmentAppointment is a window of time in which a student may enroll in classes.
+type EnrollmentAppointment struct {
+	Session string
+
+	// Name is the name of the appointment, such as "Pre-Enrollment".
+	Name string
+
+	// Start and End are shown to the minute. The appointment is open through the whole minute of
+	// its End (e.g. until midnight for an End of 11:59PM).
+	Start time.Time
+	End   time.Time
+
+	MaxTotalUnits    float64
+	MaxNoGPAUnits    float64
+	MaxAuditUnits    float64
+	MaxWaitListUnits float64
+}
+
+// Open returns true if the appointment window includes the given time.
+func (a *EnrollmentAppointment) Open(t time.Time) bool {
+	return !t.Before(a.Start) && t.Before(a.closes())
+}
+
+// closes returns the first moment after the appointment window.
+func (a *EnrollmentAppointment) closes() time.Time {
+	return a.End.Add(time.Minute)
+}
+
+// An OpenEnrollmentPeriod is the range of dates in which any student may enroll in a session.
+type OpenEnrollmentPeriod struct {
+	Session string
+	Begins  Date
+
+	// LastDay is the last day to enroll.
+	LastDay Date
+}
+
+// EnrollmentDates contains a student's enrollment appointments and the open enrollment dates for a
+// term.
+type EnrollmentDates struct {
+	Term           Term
+	Appointments   []EnrollmentAppointment
+	OpenEnrollment []OpenEnrollmentPeriod
+}
+
+// NextAppointment returns the first appointment which has not ended by the given time, or nil if
+// every appointment has ended.
+func (d *EnrollmentDates) NextAppointment(t time.Time) *EnrollmentAppointment {
+	var res *EnrollmentAppointment
+	for i := range d.Appointments {
+		appointment := &d.Appointments[i]
+		if appointment.closes().After(t) && (res == nil || appointment.Start.Before(res.Start)) {
+			res = appointment
+		}
+	}
+	return res
+}
+
+// FetchEnrollmentAppointments downloads the user's enrollment appointments and the open
+// enrollment dates for a term. Times are in the engine's time zone (see EngineTimeZone).
+func (c *Client) FetchEnrollmentAppointments(term Term) (*EnrollmentDates, error) {
+	root, selected, err := c.selectTerm(enrollmentDatesPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	dates, err := parseEnrollmentDates(root, EngineTimeZone(c.uni))
+	if err != nil {
+		return nil, err
+	}
+	dates.Term = *selected
+	return dates, nil
+}
+
+// parseEnrollmentDates parses the enrollment appointment and open enrollment tables. Students
+// without appointments have no appointment table, so neither table is required.
+func parseEnrollmentDates(root *html.Node, loc *time.Location) (*EnrollmentDates, error) {
+	var dates EnrollmentDates
+
+	if table, ok := findTableWithHeader(root, "Appointment Begins"); ok {
+		grid, err := ParseGrid(table)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range grid.Maps() {
+			appointment := EnrollmentAppointment{
+				Session: row["Session"],
+				Name:    row["Appointment"],
+			}
+			if appointment.Start, err = parseDateTime(row["Appointment Begins"], loc); err != nil {
+				return nil, err
+			}
+			if appointment.End, err = parseDateTime(row["Appointment Ends"], loc); err != nil {
+				return nil, err
+			}
+			units := []struct {
+				header string
+				value  *float64
+			}{
+				{"Max Total Units", &appointment.MaxTotalUnits},
+				{"Max No GPA Units", &appointment.MaxNoGPAUnits},
+				{"Max Audit Units", &appointment.MaxAuditUnits},
+				{"Max Wait List Units", &appointment.MaxWaitListUnits},
+			}
+			for _, u := range units {
+				if str := row[u.header]; str != "" {
+					if *u.value, err = strconv.ParseFloat(str, 64); err != nil {
+						return nil, errors.New("invalid " + u.header + ": " + str)
+					}
+				}
+			}
+			dates.Appointments = append(dates.Appointments, appointment)
+		}
+	}
+
+	if table, ok := findTableWithHeader(root, "Last Date to Enroll"); ok {
+		grid, err := ParseGrid(table)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range grid.Maps() {
+			period := OpenEnrollmentPeriod{Session: row["Session"]}
+			if period.Begins, err = ParseDate(row["Begins On"]); err != nil {
+				return nil, err
+			}
+			if period.LastDay, err = ParseDate(row["Last Date to Enroll"]); err != nil {
+				return nil, err
+			}
+			dates.OpenEnrollment = append(dates.OpenEnrollment, period)
+		}
+	}
+
+	return &dates, nil
+}
+
+// parseDateTime parses a date and time like "04/14/2015 7:00AM" in the given location.
+func parseDateTime(str string, loc *time.Location) (time.Time, error) {
+	fields := strings.Fields(str)
+	if len(fields) != 2 {
+		return time.Time{}, errors.New("invalid date and time: " + str)
+	}
+	date, err := ParseDate(fields[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	timeOfDay, err := ParseTimeOfDay(fields[1])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year, date.Month, date.Day, timeOfDay.Hour(), timeOfDay.Minute(), 0, 0,
+		loc), nil
+}
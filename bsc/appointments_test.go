@@ -0,0 +1,74 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testEnrollmentDatesPage = `<html><body>
+<table>
+<tr><th>Session</th><th>Appointment</th><th>Appointment Begins</th><th>Appointment Ends</th>
+<th>Max Total Units</th><th>Max No GPA Units</th><th>Max Audit Units</th>
+<th>Max Wait List Units</th></tr>
+<tr><td>Regular Academic Session</td><td>Pre-Enrollment</td><td>04/14/2015  7:00AM</td>
+<td>04/24/2015  11:59PM</td><td>18.00</td><td>4.00</td><td>4.00</td><td>8.00</td></tr>
+<tr><td>Regular Academic Session</td><td>Add/Drop</td><td>08/20/2015  7:00AM</td>
+<td>09/11/2015  11:59PM</td><td>23.00</td><td></td><td></td><td></td></tr>
+</table>
+<table>
+<tr><th>Session</th><th>Begins On</th><th>Last Date to Enroll</th></tr>
+<tr><td>Regular Academic Session</td><td>08/20/2015</td><td>09/11/2015</td></tr>
+</table>
+</body></html>`
+
+func TestParseEnrollmentDates(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testEnrollmentDatesPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc := EngineTimeZone(CornellEngine{})
+	dates, err := parseEnrollmentDates(root, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates.Appointments) != 2 {
+		t.Fatal("expected 2 appointments but got", len(dates.Appointments))
+	}
+	first := dates.Appointments[0]
+	if !first.Start.Equal(time.Date(2015, time.April, 14, 7, 0, 0, 0, loc)) ||
+		first.Name != "Pre-Enrollment" || first.MaxTotalUnits != 18 ||
+		first.MaxWaitListUnits != 8 {
+		t.Error("unexpected first appointment:", first)
+	}
+	if len(dates.OpenEnrollment) != 1 ||
+		dates.OpenEnrollment[0].LastDay != (Date{time.September, 11, 2015}) {
+		t.Error("unexpected open enrollment:", dates.OpenEnrollment)
+	}
+
+	now := time.Date(2015, time.May, 1, 12, 0, 0, 0, loc)
+	next := dates.NextAppointment(now)
+	if next == nil || next.Name != "Add/Drop" || next.Open(now) {
+		t.Error("unexpected next appointment:", next)
+	}
+	if !next.Open(time.Date(2015, time.August, 20, 7, 0, 0, 0, loc)) {
+		t.Error("appointment should be open when it begins")
+	}
+
+	lastMinute := time.Date(2015, time.April, 24, 23, 59, 30, 0, loc)
+	if !first.Open(lastMinute) {
+		t.Error("appointment should be open during its last minute")
+	}
+	if next := dates.NextAppointment(lastMinute); next == nil || next.Name != "Pre-Enrollment" {
+		t.Error("unexpected appointment during the last minute:", next)
+	}
+	if first.Open(time.Date(2015, time.April, 25, 0, 0, 0, 0, loc)) {
+		t.Error("appointment should close at the end of its last minute")
+	}
+}
+
+func TestEngineTimeZone(t *testing.T) {
+	if loc := EngineTimeZone(testMinimalEngine{}); loc.String() != easternTime().String() {
+		t.Error("engines without a time zone should default to Eastern time but got", loc)
+	}
+}
@@ -0,0 +1,211 @@
+package bsc
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// A ReservedCapacity is a group of seats in a class which is held for certain students.
+type ReservedCapacity struct {
+	// Description describes who the seats are reserved for, e.g. "Computer Science Majors".
+	Description string
+
+	Capacity int
+	Enrolled int
+}
+
+// Available returns the number of reserved seats which have not been filled.
+func (r ReservedCapacity) Available() int {
+	if r.Enrolled >= r.Capacity {
+		return 0
+	}
+	return r.Capacity - r.Enrolled
+}
+
+// AppliesTo returns true if a student qualifies for the reserved seats. A reservation applies if
+// its description mentions the student's career or one of their majors as whole words, so that
+// "Math" matches "Math Majors" but not "Mathematical Biology".
+func (r ReservedCapacity) AppliesTo(student StudentProfile) bool {
+	desc := words(r.Description)
+	groups := append([]string{student.Career}, student.Majors...)
+	for _, group := range groups {
+		if containsWords(desc, words(group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits a string into lower-case words, ignoring punctuation.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords returns true if phrase appears as a consecutive run of words in text. An empty
+// phrase is not contained in anything.
+func containsWords(text, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(text); i++ {
+		match := true
+		for j, word := range phrase {
+			if text[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// CombinedAvailability stores the availability of a group of combined sections. Students in any of
+// the sections count towards the group's limit.
+type CombinedAvailability struct {
+	Capacity        int
+	EnrollmentTotal int
+	AvailableSeats  int
+}
+
+// A StudentProfile describes the student groups which reserved seats are held for.
+type StudentProfile struct {
+	// Career is the student's academic career, such as "Undergraduate" or "Graduate".
+	Career string
+
+	// Majors lists the student's majors, such as "Computer Science".
+	Majors []string
+}
+
+// SeatsAvailableTo returns the number of seats in a class which a student could take. This counts
+// the unreserved seats and the reserved seats which apply to the student, and is limited by the
+// availability of any combined sections.
+func (a *ClassAvailability) SeatsAvailableTo(student StudentProfile) int {
+	reservedCapacity, reservedEnrolled := 0, 0
+	seats := 0
+	for _, r := range a.ReservedSeats {
+		reservedCapacity += r.Capacity
+		reservedEnrolled += r.Enrolled
+		if r.AppliesTo(student) {
+			seats += r.Available()
+		}
+	}
+	if unreserved := (a.Capacity - reservedCapacity) - (a.EnrollmentTotal -
+		reservedEnrolled); unreserved > 0 {
+		seats += unreserved
+	}
+
+	if seats > a.AvailableSeats {
+		seats = a.AvailableSeats
+	}
+	if a.Combined != nil && seats > a.Combined.AvailableSeats {
+		seats = a.Combined.AvailableSeats
+	}
+	if seats < 0 {
+		return 0
+	}
+	return seats
+}
+
+// parseReservedCapacity parses the "Reserve Capacity" table of a class detail page. It returns nil
+// if the class has no reserved seats.
+func parseReservedCapacity(root *html.Node) ([]ReservedCapacity, error) {
+	table, ok := findTableWithHeader(root, "Reserved For")
+	if !ok {
+		return nil, nil
+	}
+	grid, err := ParseGrid(table)
+	if err != nil {
+		return nil, err
+	}
+	var res []ReservedCapacity
+	for _, row := range grid.Maps() {
+		if row["Reserved For"] == "" {
+			continue
+		}
+		r := ReservedCapacity{Description: row["Reserved For"]}
+		if r.Capacity, err = strconv.Atoi(firstNonEmpty(row["Enrollment Capacity"],
+			row["Reserved Capacity"], row["Cap Enrl"])); err != nil {
+			return nil, err
+		}
+		if r.Enrolled, err = strconv.Atoi(firstNonEmpty(row["Enrollment Total"],
+			row["Reserved Enrolled"], row["Tot Enrl"])); err != nil {
+			return nil, err
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// parseCombinedAvailability parses the combined section totals from a class detail page. It
+// returns nil if the page has no combined section totals.
+func parseCombinedAvailability(root *html.Node) *CombinedAvailability {
+	texts := pageTexts(root)
+	var res CombinedAvailability
+	found := false
+	fields := map[string]*int{
+		"Combined Section Capacity": &res.Capacity,
+		"Combined Enrollment Total": &res.EnrollmentTotal,
+		"Combined Available Seats":  &res.AvailableSeats,
+	}
+	for label, field := range fields {
+		if value, ok := labeledNumber(texts, label); ok {
+			*field = value
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &res
+}
+
+// pageTexts returns the trimmed, non-empty text nodes of a document in order.
+func pageTexts(root *html.Node) []string {
+	var res []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			if text := strings.TrimSpace(n.Data); text != "" {
+				res = append(res, text)
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	return res
+}
+
+// labeledNumber finds a label in a list of texts and returns the number directly after it. The
+// combined section totals are laid out as label/value pairs, so the value is the next text.
+func labeledNumber(texts []string, label string) (int, bool) {
+	for i, text := range texts {
+		if !strings.EqualFold(text, label) {
+			continue
+		}
+		if i+1 < len(texts) {
+			if value, err := strconv.Atoi(texts[i+1]); err == nil {
+				return value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func firstNonEmpty(strs ...string) string {
+	for _, s := range strs {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package bsc
+
+import "testing"
+
+func TestSeatsAvailableTo(t *testing.T) {
+	availability := ClassAvailability{
+		Capacity:        100,
+		EnrollmentTotal: 75,
+		AvailableSeats:  25,
+		ReservedSeats: []ReservedCapacity{
+			{Description: "Computer Science Majors", Capacity: 30, Enrolled: 20},
+			{Description: "Graduate Students", Capacity: 10, Enrolled: 5},
+		},
+	}
+
+	// 60 unreserved seats with 50 enrolled leaves 10 for everybody.
+	cases := []struct {
+		student  StudentProfile
+		expected int
+	}{
+		{StudentProfile{Career: "Undergraduate"}, 10},
+		{StudentProfile{Career: "Undergraduate", Majors: []string{"Computer Science"}}, 10 + 10},
+		{StudentProfile{Career: "Graduate", Majors: []string{"computer science"}}, 10 + 10 + 5},
+	}
+	for i, c := range cases {
+		if seats := availability.SeatsAvailableTo(c.student); seats != c.expected {
+			t.Error("case", i, "should have", c.expected, "seats but got", seats)
+		}
+	}
+
+	availability.Combined = &CombinedAvailability{Capacity: 150, EnrollmentTotal: 147,
+		AvailableSeats: 3}
+	if seats := availability.SeatsAvailableTo(cases[1].student); seats != 3 {
+		t.Error("combined sections should limit seats to 3 but got", seats)
+	}
+}
+
+func TestReservedCapacityAppliesTo(t *testing.T) {
+	cases := []struct {
+		description string
+		student     StudentProfile
+		expected    bool
+	}{
+		{"Math Majors", StudentProfile{Majors: []string{"Math"}}, true},
+		{"Mathematical Biology Majors", StudentProfile{Majors: []string{"Math"}}, false},
+		{"Program Participants", StudentProfile{Majors: []string{"Art"}}, false},
+		{"Art, Art History Majors", StudentProfile{Majors: []string{"Art History"}}, true},
+		{"Graduate Students", StudentProfile{Career: "Undergraduate"}, false},
+		{"Graduate Students", StudentProfile{Career: "graduate"}, true},
+		{"Computer Science Majors", StudentProfile{Majors: []string{"CS"}}, false},
+	}
+	for _, c := range cases {
+		r := ReservedCapacity{Description: c.description}
+		if r.AppliesTo(c.student) != c.expected {
+			t.Errorf("%q applying to %v should be %v", c.description, c.student, c.expected)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package bsc
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var shoppingCartPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_CART.GBL" +
+	"?Page=SSR_SSENRL_CART"
+
+const (
+	cartAddAction  = "DERIVED_REGFRM1_SSR_PB_ADDTOLIST2$"
+	cartNextAction = "DERIVED_CLS_DTL_NEXT_PB$"
+
+	// maxCartSteps limits the number of pages AddToCart goes through after entering a class
+	// number, in case PeopleSoft keeps showing the same page.
+	maxCartSteps = 5
+)
+
+// A CartStatus is the status icon shown next to a class in the shopping cart.
+type CartStatus int
+
+const (
+	CartStatusUnknown CartStatus = iota
+	CartStatusOK
+	CartStatusWaitlist
+	CartStatusClosed
+	CartStatusConflict
+)
+
+// ParseCartStatus turns the alternate text of a status icon into a CartStatus.
+func ParseCartStatus(str string) CartStatus {
+	lower := strings.ToLower(strings.TrimSpace(str))
+	switch {
+	case lower == "open" || lower == "valid" || lower == "success":
+		return CartStatusOK
+	case lower == "wait list" || lower == "waitlist":
+		return CartStatusWaitlist
+	case lower == "closed":
+		return CartStatusClosed
+	case strings.Contains(lower, "conflict") || lower == "error":
+		return CartStatusConflict
+	default:
+		return CartStatusUnknown
+	}
+}
+
+// String returns a human-readable version of the CartStatus.
+func (s CartStatus) String() string {
+	names := map[CartStatus]string{
+		CartStatusOK:       "OK",
+		CartStatusWaitlist: "Wait List",
+		CartStatusClosed:   "Closed",
+		CartStatusConflict: "Conflict",
+	}
+	if name, ok := names[s]; ok {
+		return name
+	} else {
+		return "Unknown"
+	}
+}
+
+// A ShoppingCart is the list of classes a student plans to enroll in for a term.
+type ShoppingCart struct {
+	Term    Term
+	Entries []CartEntry
+}
+
+// A CartEntry is a class in a ShoppingCart.
+type CartEntry struct {
+	Department string
+	Number     string
+
+	// Component contains the class number, section, meeting times, room, and instructors.
+	Component Component
+
+	Units  float64
+	Status CartStatus
+
+	// deleteAction is the ICAction which removes the entry from the cart.
+	deleteAction string
+}
+
+// CartOptions specifies the choices to make when adding a class to the shopping cart.
+type CartOptions struct {
+	// RelatedClassNumbers lists the class numbers of the related components to enroll in, such as
+	// the lab for a lecture. If a class has only one choice for a related component, it is chosen
+	// automatically.
+	RelatedClassNumbers []int
+
+	// PermissionNumber is the permission number given by an instructor, or 0 if there is none.
+	PermissionNumber int
+
+	// GradingBasis is the code or description of the grading basis, e.g. "Letter". If it is
+	// empty, the default is used.
+	GradingBasis string
+
+	// WaitlistIfFull indicates that the student should be put on the wait list if the class is
+	// full.
+	WaitlistIfFull bool
+}
+
+// FetchShoppingCart downloads the user's shopping cart for a term.
+func (c *Client) FetchShoppingCart(term Term) (*ShoppingCart, error) {
+	root, selected, err := c.selectTerm(shoppingCartPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	return parseShoppingCart(root, *selected, c.uni)
+}
+
+// AddToCart adds a class to the user's shopping cart and returns the updated cart.
+func (c *Client) AddToCart(term Term, classNumber int, options CartOptions) (*ShoppingCart,
+	error) {
+	root, selected, err := c.selectTerm(shoppingCartPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	addAction, ok := findAction(root, cartAddAction)
+	if !ok {
+		return nil, errors.New("could not find the button to add a class")
+	}
+	values := form.submitValues(addAction)
+	err = form.setField(values, "DERIVED_REGFRM1_CLASS_NBR", strconv.Itoa(classNumber))
+	if err != nil {
+		return nil, err
+	}
+	if root, err = c.submitForm(form, values); err != nil {
+		return nil, err
+	}
+
+	if root, err = c.fillClassOptions(root, options); err != nil {
+		return nil, err
+	}
+
+	cart, err := parseShoppingCart(root, *selected, c.uni)
+	if err != nil {
+		if msg := pageErrorMessage(root); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	if _, ok := cart.entry(classNumber); !ok {
+		if msg := pageErrorMessage(root); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, errors.New("class was not added to the cart")
+	}
+	return cart, nil
+}
+
+// RemoveFromCart removes a class from the user's shopping cart and returns the updated cart.
+func (c *Client) RemoveFromCart(term Term, classNumber int) (*ShoppingCart, error) {
+	root, selected, err := c.selectTerm(shoppingCartPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	cart, err := parseShoppingCart(root, *selected, c.uni)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := cart.entry(classNumber)
+	if !ok {
+		return nil, errors.New("class is not in the cart: " + strconv.Itoa(classNumber))
+	} else if entry.deleteAction == "" {
+		return nil, errors.New("class cannot be removed from the cart")
+	}
+
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	root, err = c.submitForm(form, form.submitValues(entry.deleteAction))
+	if err != nil {
+		return nil, err
+	}
+	if msg := pageErrorMessage(root); msg != "" {
+		return nil, errors.New(msg)
+	}
+	return parseShoppingCart(root, *selected, c.uni)
+}
+
+// fillClassOptions goes through the "Related Class Sections" and "Enrollment Preferences" pages
+// which PeopleSoft shows after a class number is entered, and returns the page which follows them.
+// If root is not one of these pages, it is returned as is.
+func (c *Client) fillClassOptions(root *html.Node, options CartOptions) (*html.Node, error) {
+	for i := 0; i < maxCartSteps; i++ {
+		nextAction, ok := findAction(root, cartNextAction)
+		if !ok {
+			return root, nil
+		}
+		if msg := pageErrorMessage(root); msg != "" {
+			return nil, errors.New(msg)
+		}
+		form, err := parsePSForm(root)
+		if err != nil {
+			return nil, err
+		}
+		values := form.submitValues(nextAction)
+		err = fillRelatedComponents(root, values, options.RelatedClassNumbers)
+		if err != nil {
+			return nil, err
+		}
+		if err := fillCartPreferences(form, values, options); err != nil {
+			return nil, err
+		}
+		if root, err = c.submitForm(form, values); err != nil {
+			return nil, err
+		}
+	}
+	return nil, errors.New("too many steps after entering class")
+}
+
+func (s *ShoppingCart) entry(classNumber int) (*CartEntry, bool) {
+	for i := range s.Entries {
+		if s.Entries[i].Component.ClassNumber == classNumber {
+			return &s.Entries[i], true
+		}
+	}
+	return nil, false
+}
+
+// fillRelatedComponents chooses related components on the "Related Class Sections" page. Each
+// group of related components is a group of radio buttons, and the button whose row contains one
+// of the class numbers is chosen.
+//
+// If root is not a related sections page, this does nothing.
+func fillRelatedComponents(root *html.Node, values url.Values, classNumbers []int) error {
+	radios := scrape.FindAll(root, func(node *html.Node) bool {
+		return node.DataAtom == atom.Input &&
+			strings.ToLower(getNodeAttribute(node, "type")) == "radio" &&
+			strings.HasPrefix(getNodeAttribute(node, "name"), "SSR_CLS_TBL_R")
+	})
+	groups := map[string][]*html.Node{}
+	var groupNames []string
+	for _, radio := range radios {
+		name := getNodeAttribute(radio, "name")
+		if _, ok := groups[name]; !ok {
+			groupNames = append(groupNames, name)
+		}
+		groups[name] = append(groups[name], radio)
+	}
+
+	for _, name := range groupNames {
+		choices := groups[name]
+		var chosen *html.Node
+		var available []string
+		for _, radio := range choices {
+			rowNumbers := radioRowClassNumbers(radio)
+			available = append(available, rowNumbers...)
+			for _, num := range classNumbers {
+				for _, rowNum := range rowNumbers {
+					if rowNum == strconv.Itoa(num) {
+						chosen = radio
+					}
+				}
+			}
+		}
+		if chosen == nil && len(choices) == 1 {
+			chosen = choices[0]
+		}
+		if chosen == nil {
+			sort.Strings(available)
+			return errors.New("a related class must be chosen from: " +
+				strings.Join(available, ", "))
+		}
+		values[name] = []string{getNodeAttribute(chosen, "value")}
+	}
+	return nil
+}
+
+// radioRowClassNumbers returns the numeric cells in the table row containing a radio button,
+// which include the class number of the related section.
+func radioRowClassNumbers(radio *html.Node) []string {
+	row := radio.Parent
+	for row != nil && row.DataAtom != atom.Tr {
+		row = row.Parent
+	}
+	if row == nil {
+		return nil
+	}
+	var res []string
+	for _, cell := range rowCells(row) {
+		text := strings.TrimSpace(nodeInnerText(cell))
+		if _, err := strconv.Atoi(text); err == nil {
+			res = append(res, text)
+		}
+	}
+	return res
+}
+
+// fillCartPreferences fills in the "Enrollment Preferences" page. If the form is not for the
+// preferences page, this does nothing.
+func fillCartPreferences(form *psForm, values url.Values, options CartOptions) error {
+	if _, ok := form.fieldName("DERIVED_CLS_DTL_WAIT_LIST_OKAY$"); !ok {
+		if _, ok := form.fieldName("DERIVED_CLS_DTL_CLASS_PRMSN_NBR$"); !ok {
+			return nil
+		}
+	}
+
+	form.setCheckbox(values, "DERIVED_CLS_DTL_WAIT_LIST_OKAY$", options.WaitlistIfFull)
+	if options.PermissionNumber != 0 {
+		err := form.setField(values, "DERIVED_CLS_DTL_CLASS_PRMSN_NBR$",
+			strconv.Itoa(options.PermissionNumber))
+		if err != nil {
+			return err
+		}
+	}
+	if options.GradingBasis != "" {
+		err := form.setSelect(values, "DERIVED_CLS_DTL_GRADING_BASIS$", options.GradingBasis)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseShoppingCart parses the shopping cart table. The engine is used to parse the location of
+// each entry's component.
+func parseShoppingCart(root *html.Node, term Term, uni UniversityEngine) (*ShoppingCart, error) {
+	cart := &ShoppingCart{Term: term}
+
+	table, ok := findTableWithHeader(root, "Days/Times")
+	if !ok {
+		if _, ok := findAction(root, cartAddAction); ok {
+			// The cart is empty.
+			return cart, nil
+		}
+		return nil, errors.New("could not find shopping cart")
+	}
+	grid, err := ParseGrid(table)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range grid.Rows {
+		classCell := grid.Cell(i, "Class")
+		if classCell == nil || classCell.Text == "" {
+			continue
+		}
+		entry, err := parseCartEntry(grid, i)
+		if err != nil {
+			return nil, err
+		}
+		entry.Component.Location = EngineParseLocation(uni, entry.Component.Room)
+		cart.Entries = append(cart.Entries, entry)
+	}
+	return cart, nil
+}
+
+// parseCartEntry parses a row of the shopping cart table. The class cell looks like
+// "CS 2110-001 (1234)", with the class number on its own line.
+func parseCartEntry(grid *Grid, row int) (CartEntry, error) {
+	var entry CartEntry
+	classCell := grid.Cell(row, "Class")
+
+	match := classNumberInParens.FindStringSubmatch(classCell.Text)
+	if match == nil {
+		return entry, errors.New("missing class number: " + classCell.Text)
+	}
+	entry.Component.ClassNumber, _ = strconv.Atoi(match[1])
+	if len(classCell.Lines) > 0 {
+		fields := strings.Fields(classCell.Lines[0])
+		if len(fields) == 2 {
+			entry.Department = fields[0]
+			numberSection := strings.SplitN(fields[1], "-", 2)
+			entry.Number = numberSection[0]
+			if len(numberSection) == 2 {
+				entry.Component.Section = numberSection[1]
+			}
+		}
+	}
+
+	if cell := grid.Cell(row, "Days/Times"); cell != nil {
+		if weeklyTimes, err := ParseWeeklyTimes(cell.Text); err == nil {
+			entry.Component.WeeklyTimes = *weeklyTimes
+		}
+	}
+	if cell := grid.Cell(row, "Room"); cell != nil {
+		entry.Component.Room = cell.Text
+	}
+	entry.Component.Instructors = parseInstructorCell(grid.Cell(row, "Instructor"))
+	if cell := grid.Cell(row, "Units"); cell != nil && cell.Text != "" {
+		units, err := strconv.ParseFloat(cell.Text, 64)
+		if err != nil {
+			return entry, errors.New("invalid units: " + cell.Text)
+		}
+		entry.Units = units
+	}
+	if cell := grid.Cell(row, "Status"); cell != nil && cell.Node != nil {
+		if img, ok := scrape.Find(cell.Node, scrape.ByTag(atom.Img)); ok {
+			entry.Status = ParseCartStatus(getNodeAttribute(img, "alt"))
+		}
+	}
+	if cell := grid.Cell(row, "Delete"); cell != nil && cell.Node != nil {
+		if action, ok := findAction(cell.Node, ""); ok {
+			entry.deleteAction = action
+		}
+	}
+	return entry, nil
+}
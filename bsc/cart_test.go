@@ -0,0 +1,97 @@
+package bsc
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testShoppingCartPage = `<html><body>
+<form class="PSForm" action="SSR_SSENRL_CART.GBL">
+<input type="hidden" name="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" value="4">
+<input type="text" name="DERIVED_REGFRM1_CLASS_NBR" value="">
+<input type="button" id="DERIVED_REGFRM1_SSR_PB_ADDTOLIST2$9$" value="enter">
+<table id="SSR_REGFORM_VW$scroll$0">
+<tr><th>Delete</th><th>Class</th><th>Days/Times</th><th>Room</th><th>Instructor</th>
+<th>Units</th><th>Status</th></tr>
+<tr><td><a id="P_DELETE$0"><img alt="Delete"></a></td>
+<td>CS 2110-001<br>(1234)</td><td>MoWeFr 10:10AM - 11:00AM</td><td>Olin Hall 155</td>
+<td>Jane Doe</td><td>4.00</td><td><img alt="Open" src="open.gif"></td></tr>
+<tr><td><a id="P_DELETE$1"><img alt="Delete"></a></td>
+<td>MATH 2940-002<br>(2001)</td><td>TBA</td><td>TBA</td><td>Staff</td><td>4.00</td>
+<td><img alt="Time Conflict" src="conflict.gif"></td></tr>
+</table>
+</form>
+</body></html>`
+
+func TestParseShoppingCart(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testShoppingCartPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cart, err := parseShoppingCart(root, Term{Description: "Fall 2015"}, CornellEngine{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cart.Entries) != 2 {
+		t.Fatal("expected 2 entries but got", len(cart.Entries))
+	}
+	first := cart.Entries[0]
+	if first.Department != "CS" || first.Number != "2110" || first.Component.Section != "001" ||
+		first.Component.ClassNumber != 1234 || first.Units != 4 ||
+		first.Status != CartStatusOK || first.deleteAction != "P_DELETE$0" {
+		t.Error("unexpected first entry:", first)
+	}
+	if first.Component.Location.Raw != "Olin Hall 155" ||
+		first.Component.Location.Room != "155" {
+		t.Error("unexpected location:", first.Component.Location)
+	}
+	if !cart.Entries[1].Component.Location.TBA {
+		t.Error("expected a TBA location but got", cart.Entries[1].Component.Location)
+	}
+	if cart.Entries[1].Status != CartStatusConflict {
+		t.Error("expected a conflict but got", cart.Entries[1].Status)
+	}
+	if _, ok := cart.entry(2001); !ok {
+		t.Error("could not find entry by class number")
+	}
+}
+
+const testRelatedSectionsPage = `<html><body>
+<table>
+<tr><th>Select</th><th>Class Nbr</th><th>Section</th></tr>
+<tr><td><input type="radio" name="SSR_CLS_TBL_R1$sels$0" value="0"></td><td>1301</td>
+<td>LAB 401</td></tr>
+<tr><td><input type="radio" name="SSR_CLS_TBL_R1$sels$0" value="1"></td><td>1302</td>
+<td>LAB 402</td></tr>
+</table>
+<table>
+<tr><th>Select</th><th>Class Nbr</th><th>Section</th></tr>
+<tr><td><input type="radio" name="SSR_CLS_TBL_R2$sels$0" value="0"></td><td>1401</td>
+<td>DIS 201</td></tr>
+</table>
+</body></html>`
+
+func TestFillRelatedComponents(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testRelatedSectionsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := url.Values{}
+	if err := fillRelatedComponents(root, values, []int{1302}); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("SSR_CLS_TBL_R1$sels$0") != "1" {
+		t.Error("wrong lab chosen:", values.Get("SSR_CLS_TBL_R1$sels$0"))
+	}
+	if values.Get("SSR_CLS_TBL_R2$sels$0") != "0" {
+		t.Error("the only discussion should be chosen automatically")
+	}
+
+	if err := fillRelatedComponents(root, url.Values{}, nil); err == nil ||
+		!strings.Contains(err.Error(), "1301, 1302") {
+		t.Error("expected an error listing the labs but got", err)
+	}
+}
@@ -0,0 +1,333 @@
+package bsc
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var catalogPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSS_BROWSE_CATLG_P.GBL"
+var guestCatalogPath string = "/EMPLOYEE/HRMS/c/COMMUNITY_ACCESS.SSS_BROWSE_CATLG.GBL"
+
+const (
+	catalogLetterActionPrefix = "DERIVED_SSS_BCC_SSR_ALPHANUM_"
+	catalogExpandAllPrefix    = "DERIVED_SSS_BCC_SSR_EXPAND_ALL$"
+	catalogSubjectPrefix      = "DERIVED_SSS_BCC_GROUP_BOX_1$"
+)
+
+// A Subject is a subject area in the course catalog, such as "CS" ("Computer Science").
+type Subject struct {
+	Code        string
+	Description string
+}
+
+// A CatalogCourse is a course in the course catalog.
+//
+// Courses from ListCatalogCourses only have a Subject, Number, and Title. The other fields are
+// filled in by FetchCatalogEntry.
+type CatalogCourse struct {
+	Subject string
+	Number  string
+	Title   string
+
+	Description string
+
+	// MinUnits and MaxUnits are equal for courses with a fixed number of units.
+	MinUnits float64
+	MaxUnits float64
+
+	Grading string
+
+	// Components lists the course's components as shown in the catalog, such as "Lecture
+	// Required".
+	Components []string
+
+	EnrollmentRequirements string
+
+	// TypicallyOffered lists the terms in which the course is usually offered, such as "Fall".
+	TypicallyOffered []string
+
+	// EquivalentCourses lists courses which count as the same course, such as "ECE 2400".
+	EquivalentCourses []string
+}
+
+// ID returns the identifier to pass to FetchCatalogEntry, such as "CS 2110".
+func (c *CatalogCourse) ID() string {
+	return c.Subject + " " + c.Number
+}
+
+// Matches returns true if a course in a schedule is an offering of this catalog course.
+func (c *CatalogCourse) Matches(course Course) bool {
+	return c.Subject == course.Department && c.Number == course.Number
+}
+
+// ListSubjects lists the subjects in the course catalog.
+//
+// PeopleSoft lists subjects by their first letter, so this makes one request for every letter.
+// The course catalog is available to guest clients.
+func (c *Client) ListSubjects() ([]Subject, error) {
+	root, err := c.fetchPage(c.publicPage(catalogPath, guestCatalogPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var letterActions []string
+	for _, node := range scrape.FindAll(root, func(node *html.Node) bool {
+		return (node.DataAtom == atom.A || node.DataAtom == atom.Input) &&
+			strings.HasPrefix(getNodeAttribute(node, "id"), catalogLetterActionPrefix)
+	}) {
+		letterActions = append(letterActions, getNodeAttribute(node, "id"))
+	}
+	if len(letterActions) == 0 {
+		return nil, errors.New("could not find catalog letters")
+	}
+
+	var subjects []Subject
+	seen := map[string]bool{}
+	for _, action := range letterActions {
+		root, err = c.clickCatalogAction(root, action)
+		if err != nil {
+			return nil, err
+		}
+		for _, subject := range parseCatalogSubjects(root) {
+			if !seen[subject.Code] {
+				seen[subject.Code] = true
+				subjects = append(subjects, subject)
+			}
+		}
+	}
+	return subjects, nil
+}
+
+// ListCatalogCourses lists the courses under a subject in the course catalog. The subject is a
+// subject code such as "CS".
+func (c *Client) ListCatalogCourses(subject string) ([]CatalogCourse, error) {
+	listings, _, err := c.openCatalogSubject(subject)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]CatalogCourse, len(listings))
+	for i, listing := range listings {
+		res[i] = listing.course
+	}
+	return res, nil
+}
+
+// FetchCatalogEntry downloads the full catalog entry for a course. The courseID identifies the
+// course by subject and number, such as "CS 2110", as returned by CatalogCourse.ID.
+func (c *Client) FetchCatalogEntry(courseID string) (*CatalogCourse, error) {
+	detailPage, listing, err := c.openCatalogEntry(courseID)
+	if err != nil {
+		return nil, err
+	}
+	course := parseCatalogEntry(detailPage)
+	course.Subject, course.Number = listing.course.Subject, listing.course.Number
+	if course.Title == "" {
+		course.Title = listing.course.Title
+	}
+	return course, nil
+}
+
+// openCatalogEntry navigates to a course's catalog entry page.
+func (c *Client) openCatalogEntry(courseID string) (*html.Node, *catalogListing, error) {
+	fields := strings.Fields(courseID)
+	if len(fields) != 2 {
+		return nil, nil, errors.New("invalid course ID: " + courseID)
+	}
+	listings, root, err := c.openCatalogSubject(fields[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, listing := range listings {
+		if listing.course.Number != fields[1] {
+			continue
+		}
+		detailPage, err := c.clickCatalogAction(root, listing.action)
+		if err != nil {
+			return nil, nil, err
+		}
+		return detailPage, &listings[i], nil
+	}
+	return nil, nil, errors.New("course not found in catalog: " + courseID)
+}
+
+// A catalogListing is a course in the catalog's list of courses, along with the ICAction which
+// opens its catalog entry.
+type catalogListing struct {
+	course CatalogCourse
+	action string
+}
+
+// openCatalogSubject shows the courses for a subject in the course catalog. It returns the
+// listings for the subject and the page they are on.
+func (c *Client) openCatalogSubject(subject string) ([]catalogListing, *html.Node, error) {
+	if subject == "" {
+		return nil, nil, errors.New("empty subject")
+	}
+	root, err := c.fetchPage(c.publicPage(catalogPath, guestCatalogPath))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	letter := strings.ToUpper(subject[:1])
+	if action, ok := findAction(root, catalogLetterActionPrefix+letter); ok {
+		if root, err = c.clickCatalogAction(root, action); err != nil {
+			return nil, nil, err
+		}
+	}
+	if action, ok := findAction(root, catalogExpandAllPrefix); ok {
+		if root, err = c.clickCatalogAction(root, action); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var res []catalogListing
+	for _, listing := range parseCatalogListings(root) {
+		if strings.EqualFold(listing.course.Subject, subject) {
+			res = append(res, listing)
+		}
+	}
+	if len(res) == 0 {
+		if msg := pageErrorMessage(root); msg != "" {
+			return nil, nil, errors.New(msg)
+		}
+		return nil, nil, errors.New("subject not found in catalog: " + subject)
+	}
+	return res, root, nil
+}
+
+// clickCatalogAction triggers an ICAction on a catalog page.
+func (c *Client) clickCatalogAction(root *html.Node, action string) (*html.Node, error) {
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	return c.submitForm(form, form.submitValues(action))
+}
+
+// parseCatalogSubjects parses the subject headings on a catalog page, which look like "CS -
+// Computer Science".
+func parseCatalogSubjects(root *html.Node) []Subject {
+	var res []Subject
+	for _, node := range scrape.FindAll(root, byIDPrefix(catalogSubjectPrefix)) {
+		if subject, ok := parseCatalogSubject(nodeInnerText(node)); ok {
+			res = append(res, subject)
+		}
+	}
+	return res
+}
+
+func parseCatalogSubject(text string) (Subject, bool) {
+	text = strings.Join(strings.Fields(text), " ")
+	parts := strings.SplitN(text, " - ", 2)
+	if len(parts) != 2 {
+		return Subject{}, false
+	}
+	return Subject{Code: parts[0], Description: parts[1]}, true
+}
+
+// parseCatalogListings parses the courses on a catalog page. Each course belongs to the subject
+// heading which precedes it.
+func parseCatalogListings(root *html.Node) []catalogListing {
+	var res []catalogListing
+	var subject Subject
+	nodes := scrape.FindAll(root, func(node *html.Node) bool {
+		id := getNodeAttribute(node, "id")
+		return strings.HasPrefix(id, catalogSubjectPrefix) ||
+			(node.DataAtom == atom.A && strings.HasPrefix(id, "CRSE_NBR$"))
+	})
+	for _, node := range nodes {
+		id := getNodeAttribute(node, "id")
+		if strings.HasPrefix(id, catalogSubjectPrefix) {
+			subject, _ = parseCatalogSubject(nodeInnerText(node))
+			continue
+		} else if subject.Code == "" {
+			continue
+		}
+		index := strings.TrimPrefix(id, "CRSE_NBR$")
+		res = append(res, catalogListing{
+			course: CatalogCourse{
+				Subject: subject.Code,
+				Number:  strings.TrimSpace(nodeInnerText(node)),
+				Title:   spanTextByID(root, "CRSE_TITLE$"+index),
+			},
+			action: id,
+		})
+	}
+	return res
+}
+
+// parseCatalogEntry parses a course's catalog entry page. The fields are found by their labels,
+// since the ids of the fields vary between PeopleSoft versions.
+func parseCatalogEntry(root *html.Node) *CatalogCourse {
+	var course CatalogCourse
+
+	if title, ok := scrape.Find(root, byIDPrefix("DERIVED_CRSECAT_DESCR200")); ok {
+		text := strings.Join(strings.Fields(nodeInnerText(title)), " ")
+		if dashIndex := strings.Index(text, " - "); dashIndex >= 0 {
+			course.Title = text[dashIndex+3:]
+		} else {
+			course.Title = text
+		}
+	}
+
+	course.Description = strings.Join(labeledLines(root, "Description"), "\n")
+	if course.Description == "" {
+		course.Description = detailTextByID(root, "SSR_CRSE_OFF_VW_DESCRLONG")
+	}
+	course.MinUnits, course.MaxUnits = parseUnitsRange(strings.Join(labeledLines(root, "Units"),
+		" "))
+	course.Grading = strings.Join(labeledLines(root, "Grading Basis"), " ")
+	course.Components = labeledLines(root, "Course Components")
+	course.EnrollmentRequirements = strings.Join(labeledLines(root, "Enrollment Requirement"),
+		"\n")
+	for _, line := range labeledLines(root, "Typically Offered") {
+		for _, term := range strings.Split(line, ",") {
+			if term = strings.TrimSpace(term); term != "" {
+				course.TypicallyOffered = append(course.TypicallyOffered, term)
+			}
+		}
+	}
+	course.EquivalentCourses = labeledLines(root, "Equivalent Courses")
+
+	return &course
+}
+
+// labeledLines finds the <label> with the given text and returns the lines of the field it labels.
+// It returns nil if there is no such label. Labels without a "for" attribute are ignored, since
+// they do not point to a field.
+func labeledLines(root *html.Node, label string) []string {
+	labelNode, ok := scrape.Find(root, func(node *html.Node) bool {
+		return node.DataAtom == atom.Label && getNodeAttribute(node, "for") != "" &&
+			strings.EqualFold(strings.TrimSpace(nodeInnerText(node)), label)
+	})
+	if !ok {
+		return nil
+	}
+	field, ok := scrape.Find(root, scrape.ById(getNodeAttribute(labelNode, "for")))
+	if !ok {
+		return nil
+	}
+	return nodeLines(field)
+}
+
+// parseUnitsRange parses a number of units like "3.00" or "1.00 - 4.00".
+func parseUnitsRange(str string) (min, max float64) {
+	var nums []float64
+	for _, field := range strings.FieldsFunc(str, func(r rune) bool {
+		return r != '.' && !unicode.IsDigit(r)
+	}) {
+		if num, err := strconv.ParseFloat(field, 64); err == nil {
+			nums = append(nums, num)
+		}
+	}
+	if len(nums) == 0 {
+		return 0, 0
+	}
+	return nums[0], nums[len(nums)-1]
+}
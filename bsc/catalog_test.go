@@ -0,0 +1,113 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCatalogListPage = `<html><body>
+<a id="DERIVED_SSS_BCC_SSR_ALPHANUM_C">C</a>
+<div id="DERIVED_SSS_BCC_GROUP_BOX_1$84$$0">CHEM - Chemistry</div>
+<table>
+<tr><td><a id="CRSE_NBR$0">1560</a></td><td><a id="CRSE_TITLE$0">General Chemistry</a></td></tr>
+</table>
+<div id="DERIVED_SSS_BCC_GROUP_BOX_1$84$$1">CS&nbsp;- Computer Science</div>
+<table>
+<tr><td><a id="CRSE_NBR$1">1110</a></td><td><a id="CRSE_TITLE$1">Intro to Computing</a></td></tr>
+<tr><td><a id="CRSE_NBR$2">2110</a></td><td><a id="CRSE_TITLE$2">OO Programming</a></td></tr>
+</table>
+</body></html>`
+
+func TestParseCatalogListings(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testCatalogListPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subjects := parseCatalogSubjects(root)
+	if len(subjects) != 2 || subjects[1] != (Subject{"CS", "Computer Science"}) {
+		t.Error("unexpected subjects:", subjects)
+	}
+
+	listings := parseCatalogListings(root)
+	if len(listings) != 3 {
+		t.Fatal("expected 3 listings but got", len(listings))
+	}
+	last := listings[2]
+	if last.course.ID() != "CS 2110" || last.course.Title != "OO Programming" ||
+		last.action != "CRSE_NBR$2" {
+		t.Error("unexpected listing:", last)
+	}
+	if listings[0].course.Subject != "CHEM" {
+		t.Error("unexpected subject for first listing:", listings[0].course)
+	}
+}
+
+const testCatalogEntryPage = `<html><body>
+<span id="DERIVED_CRSECAT_DESCR200">CS 2110 - Object-Oriented Programming</span>
+<label for="SSR_CRSE_OFF_VW_DESCRLONG$0">Description</label>
+<span id="SSR_CRSE_OFF_VW_DESCRLONG$0">Intermediate programming in Java.</span>
+<label for="DERIVED_CRSECAT_UNITS_RANGE$0">Units</label>
+<span id="DERIVED_CRSECAT_UNITS_RANGE$0">3.00 - 4.00</span>
+<label for="SSR_CRSE_OFF_VW_GRADING_BASIS$0">Grading Basis</label>
+<span id="SSR_CRSE_OFF_VW_GRADING_BASIS$0">Student Option</span>
+<label for="DERIVED_CRSECAT_SSR_COMPONENTS$0">Course Components</label>
+<span id="DERIVED_CRSECAT_SSR_COMPONENTS$0">Lecture Required<br>Discussion Required</span>
+<label for="DERIVED_CRSECAT_DESCR254A$0">Enrollment Requirement</label>
+<span id="DERIVED_CRSECAT_DESCR254A$0">Prerequisite: CS 1110.</span>
+<label for="SSR_CRSE_TYPOFF_DESCR$0">Typically Offered</label>
+<span id="SSR_CRSE_TYPOFF_DESCR$0">Fall, Spring</span>
+<label for="DERIVED_CRSECAT_EQUIV$0">Equivalent Courses</label>
+<span id="DERIVED_CRSECAT_EQUIV$0">ENGRD 2110</span>
+</body></html>`
+
+func TestParseCatalogEntry(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testCatalogEntryPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	course := parseCatalogEntry(root)
+	if course.Title != "Object-Oriented Programming" ||
+		course.Description != "Intermediate programming in Java." {
+		t.Error("unexpected title or description:", course)
+	}
+	if course.MinUnits != 3 || course.MaxUnits != 4 || course.Grading != "Student Option" {
+		t.Error("unexpected units or grading:", course)
+	}
+	if len(course.Components) != 2 || course.Components[1] != "Discussion Required" {
+		t.Error("unexpected components:", course.Components)
+	}
+	if len(course.TypicallyOffered) != 2 || course.TypicallyOffered[1] != "Spring" {
+		t.Error("unexpected terms offered:", course.TypicallyOffered)
+	}
+	if len(course.EquivalentCourses) != 1 || course.EquivalentCourses[0] != "ENGRD 2110" {
+		t.Error("unexpected equivalent courses:", course.EquivalentCourses)
+	}
+	if course.EnrollmentRequirements != "Prerequisite: CS 1110." {
+		t.Error("unexpected requirements:", course.EnrollmentRequirements)
+	}
+
+	course.Subject, course.Number = "CS", "2110"
+	if !course.Matches(Course{Department: "CS", Number: "2110"}) {
+		t.Error("catalog course should match its schedule course")
+	}
+}
+
+func TestLabeledLinesWithoutFor(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(`<html><body>
+<label>Grading Basis</label><span>Student Option</span>
+<label>Course Components</label>
+<label for="SSR_CRSE_OFF_VW_DESCR">Typically Offered</label>
+<span id="SSR_CRSE_OFF_VW_DESCR">Fall, Spring</span>
+</body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := labeledLines(root, "Grading Basis"); lines != nil {
+		t.Error("labels without a for attribute should be ignored but got", lines)
+	}
+	if lines := labeledLines(root, "Typically Offered"); len(lines) != 1 ||
+		lines[0] != "Fall, Spring" {
+		t.Error("unexpected lines:", lines)
+	}
+}
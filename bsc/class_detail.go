@@ -0,0 +1,218 @@
+package bsc
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ClassDetail stores the descriptive information from a class's "Class Detail" page.
+//
+// Fields which a university does not show are left empty.
+type ClassDetail struct {
+	// Status is the enrollment status of the class, such as "Open", "Closed", or "Wait List".
+	Status string
+
+	Description            string
+	EnrollmentRequirements string
+
+	// Attributes lists the class attributes, such as "Writing Intensive".
+	Attributes []string
+
+	Notes           string
+	InstructionMode string
+	Campus          string
+	Location        string
+
+	// Instructors lists the instructors from the class's meeting information, without duplicates.
+	Instructors []Instructor
+
+	// CombinedSections lists the sections which are combined with this class, if any. Combined
+	// sections meet together and share enrollment limits.
+	CombinedSections []CombinedSection
+
+	// Textbooks is the textbook information, which universities usually provide as free text.
+	Textbooks string
+}
+
+// A CombinedSection is a section listed in the "Combined Section" table of a class detail page.
+type CombinedSection struct {
+	// Class is the description of the section, such as "CS 5110-001 Lecture (12345)".
+	Class string
+
+	// ClassNumber is 0 if the description does not include a class number.
+	ClassNumber int
+
+	Status string
+}
+
+var classNumberInParens = regexp.MustCompile(`\((\d+)\)`)
+
+// parseClassDetailResponse parses a class detail page, filling in the component's availability and,
+// if wantDetail is true, its Detail. It returns whether or not the class is open.
+//
+// The Detail is filled in even if the availability cannot be parsed, since the two are laid out
+// independently. In that case, the availability error is returned. Parts of the availability which
+// are optional, such as reserved seats, are reported to the warningCollector instead.
+func parseClassDetailResponse(body io.Reader, component *Component, wantDetail bool,
+	warnings *warningCollector) (bool, error) {
+	root, err := parseHTMLDocument(body)
+	if err != nil {
+		return false, err
+	}
+	if wantDetail {
+		detail := parseClassDetail(root)
+		component.Detail = &detail
+	}
+	return parseExtraComponentInfo(root, component, warnings)
+}
+
+// parseClassDetail reads the descriptive fields from a class detail page.
+func parseClassDetail(root *html.Node) ClassDetail {
+	detail := ClassDetail{
+		Status:                 detailTextByID(root, "SSR_CLS_DTL_WRK_SSR_DESCRSHORT"),
+		Description:            detailTextByID(root, "DERIVED_CLSRCH_DESCRLONG"),
+		EnrollmentRequirements: detailTextByID(root, "SSR_CLS_DTL_WRK_SSR_REQUISITE_LONG"),
+		Notes:                  detailTextByID(root, "DERIVED_CLSRCH_SSR_CLASSNOTE_LONG"),
+		InstructionMode:        detailTextByID(root, "INSTRUCT_MODE_DESCR"),
+		Campus:                 detailTextByID(root, "CAMPUS_TBL_DESCR"),
+		Location:               detailTextByID(root, "CAMPUS_LOC_VW_DESCR"),
+		Textbooks:              detailTextByID(root, "DERIVED_CLSRCH_SSR_TXB"),
+	}
+
+	if attrs, ok := scrape.Find(root, byIDPrefix("SSR_CLS_DTL_WRK_SSR_CRSE_ATTR_LONG")); ok {
+		detail.Attributes = nodeLines(attrs)
+	}
+
+	if table, ok := findTableWithHeader(root, "Days & Times"); ok {
+		if grid, err := ParseGrid(table); err == nil {
+			detail.Instructors = meetingInstructors(grid)
+		}
+	}
+
+	if combined, ok := scrape.Find(root, byIDPrefix("win0divSCTN_CMBND")); ok {
+		if table, ok := findTableWithHeader(combined, "Class"); ok {
+			if grid, err := ParseGrid(table); err == nil {
+				for _, row := range grid.Maps() {
+					section := CombinedSection{Class: row["Class"], Status: row["Status"]}
+					if match := classNumberInParens.FindStringSubmatch(section.Class); match != nil {
+						section.ClassNumber, _ = strconv.Atoi(match[1])
+					}
+					detail.CombinedSections = append(detail.CombinedSections, section)
+				}
+			}
+		}
+	}
+
+	return detail
+}
+
+// meetingInstructors returns the instructors in every row of a class detail page's meeting
+// table. Instructors who teach several meetings are only listed once.
+func meetingInstructors(grid *Grid) []Instructor {
+	res := []Instructor{}
+	seen := map[string]bool{}
+	for i := range grid.Rows {
+		for _, instructor := range parseInstructorCell(grid.Cell(i, "Instructor")) {
+			if !seen[instructor.Name] {
+				seen[instructor.Name] = true
+				res = append(res, instructor)
+			}
+		}
+	}
+	return res
+}
+
+// detailTextByID returns the text of the first element whose id begins with prefix, with <br>
+// elements turned into newlines. It returns "" if no such element exists.
+func detailTextByID(root *html.Node, prefix string) string {
+	if node, ok := scrape.Find(root, byIDPrefix(prefix)); ok {
+		return strings.Join(nodeLines(node), "\n")
+	}
+	return ""
+}
+
+// parseExtraComponentInfo parses the open status and availability from the "Class Detail" page for
+// a component.
+//
+// If the reserved seats cannot be parsed, they are reported to the warningCollector and left nil,
+// and the rest of the availability is still filled in.
+func parseExtraComponentInfo(root *html.Node, component *Component,
+	warnings *warningCollector) (courseOpen bool, err error) {
+	openStatus, ok := scrape.Find(root, scrape.ById("SSR_CLS_DTL_WRK_SSR_DESCRSHORT"))
+	if !ok {
+		return false, errors.New("open status not found")
+	}
+	courseOpen = (nodeInnerText(openStatus) == "Open")
+
+	availTable, ok := scrape.Find(root, scrape.ById("ACE_SSR_CLS_DTL_WRK_GROUP3"))
+	if !ok {
+		return courseOpen, errors.New("could not find availability info")
+	}
+
+	rows := scrape.FindAll(availTable, scrape.ByTag(atom.Tr))
+	if len(rows) != 7 {
+		return courseOpen, errors.New("invalid number of rows in availability table")
+	}
+
+	var availability ClassAvailability
+
+	cols := nodesWithAlignAttribute(scrape.FindAll(rows[2], scrape.ByTag(atom.Td)))
+	if len(cols) != 2 {
+		return courseOpen, errors.New("expected 2 aligned columns in row 2")
+	}
+	availability.Capacity, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[0])))
+	if err != nil {
+		return
+	}
+	availability.WaitListCapacity, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[1])))
+	if err != nil {
+		return
+	}
+
+	cols = nodesWithAlignAttribute(scrape.FindAll(rows[4], scrape.ByTag(atom.Td)))
+	if len(cols) != 2 {
+		return courseOpen, errors.New("expected 2 aligned columns in row 4")
+	}
+	availability.EnrollmentTotal, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[0])))
+	if err != nil {
+		return
+	}
+	availability.WaitListTotal, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[1])))
+	if err != nil {
+		return
+	}
+
+	cols = nodesWithAlignAttribute(scrape.FindAll(rows[6], scrape.ByTag(atom.Td)))
+	if len(cols) != 1 {
+		return courseOpen, errors.New("expected 1 aligned column in row 6")
+	}
+	availability.AvailableSeats, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[0])))
+	if err != nil {
+		return
+	}
+
+	if reserved, reservedErr := parseReservedCapacity(root); reservedErr != nil {
+		err = warnings.add(ParseWarning{
+			Component: component.Section,
+			Field:     "Reserved Seats",
+			Err:       reservedErr,
+		})
+		if err != nil {
+			return
+		}
+	} else {
+		availability.ReservedSeats = reserved
+	}
+	availability.Combined = parseCombinedAvailability(root)
+
+	component.ClassAvailability = &availability
+
+	return
+}
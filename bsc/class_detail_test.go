@@ -0,0 +1,135 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testClassDetailPage = `<html><body>
+<span id="SSR_CLS_DTL_WRK_SSR_DESCRSHORT">Open</span>
+<span id="CAMPUS_TBL_DESCR">Main Campus</span>
+<span id="CAMPUS_LOC_VW_DESCR">Ithaca, NY</span>
+<span id="INSTRUCT_MODE_DESCR">In Person</span>
+<span id="DERIVED_CLSRCH_DESCRLONG">Intro to programming.<br>Covers Java.</span>
+<span id="SSR_CLS_DTL_WRK_SSR_REQUISITE_LONG">Prerequisite: CS 1110.</span>
+<span id="SSR_CLS_DTL_WRK_SSR_CRSE_ATTR_LONG">Writing Intensive<br>Quantitative Reasoning</span>
+<table>
+<tr><th>Days &amp; Times</th><th>Room</th><th>Instructor</th><th>Meeting Dates</th></tr>
+<tr><td>MoWe 10:10AM - 11:00AM</td><td>Olin Hall 155</td>
+<td><a href="mailto:jd1@cornell.edu">Jane Doe</a>,<br>Doe, John</td>
+<td>08/27/2015 - 12/08/2015</td></tr>
+<tr><td>Fr 10:10AM - 11:00AM</td><td>Olin Hall 165</td><td>Jane Doe</td>
+<td>08/27/2015 - 12/08/2015</td></tr>
+</table>
+<table id="ACE_SSR_CLS_DTL_WRK_GROUP3">
+<tr><td>Class Availability</td></tr>
+<tr><td>Class Capacity</td><td>Wait List Capacity</td></tr>
+<tr><td align="left">100</td><td align="left">20</td></tr>
+<tr><td>Enrollment Total</td><td>Wait List Total</td></tr>
+<tr><td align="left">95</td><td align="left">0</td></tr>
+<tr><td>Available Seats</td></tr>
+<tr><td align="left">5</td></tr>
+</table>
+<table>
+<tr><th>Reserved For</th><th>Enrollment Capacity</th><th>Enrollment Total</th></tr>
+<tr><td>Computer Science Majors</td><td>20</td><td>18</td></tr>
+</table>
+<table><tr><td>Combined Section Capacity</td><td>150</td>
+<td>Combined Enrollment Total</td><td>148</td>
+<td>Combined Available Seats</td><td>2</td></tr></table>
+<div id="win0divSCTN_CMBND$0"><table>
+<tr><th>Class</th><th>Status</th></tr>
+<tr><td>CS 5110-001 Lecture (12345)</td><td>Open</td></tr>
+</table></div>
+</body></html>`
+
+func TestParseClassDetail(t *testing.T) {
+	var component Component
+	open, err := parseClassDetailResponse(strings.NewReader(testClassDetailPage), &component, true,
+		&warningCollector{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !open {
+		t.Error("class should be open")
+	}
+
+	avail := component.ClassAvailability
+	if avail == nil || avail.Capacity != 100 || avail.WaitListCapacity != 20 ||
+		avail.EnrollmentTotal != 95 || avail.AvailableSeats != 5 {
+		t.Error("unexpected availability:", avail)
+	}
+
+	if len(avail.ReservedSeats) != 1 || avail.ReservedSeats[0] != (ReservedCapacity{
+		"Computer Science Majors", 20, 18}) {
+		t.Error("unexpected reserved seats:", avail.ReservedSeats)
+	}
+	if avail.Combined == nil || *avail.Combined != (CombinedAvailability{150, 148, 2}) {
+		t.Error("unexpected combined availability:", avail.Combined)
+	}
+
+	detail := component.Detail
+	if detail == nil {
+		t.Fatal("missing detail")
+	}
+	if detail.Description != "Intro to programming.\nCovers Java." {
+		t.Error("unexpected description:", detail.Description)
+	}
+	if len(detail.Attributes) != 2 || detail.Attributes[0] != "Writing Intensive" {
+		t.Error("unexpected attributes:", detail.Attributes)
+	}
+	if detail.Campus != "Main Campus" || detail.InstructionMode != "In Person" {
+		t.Error("unexpected campus or instruction mode:", detail)
+	}
+	if len(detail.CombinedSections) != 1 || detail.CombinedSections[0].ClassNumber != 12345 {
+		t.Error("unexpected combined sections:", detail.CombinedSections)
+	}
+	if len(detail.Instructors) != 2 || detail.Instructors[0].Email != "jd1@cornell.edu" ||
+		detail.Instructors[1].LastName != "Doe" || detail.Instructors[1].FirstName != "John" {
+		t.Error("unexpected instructors:", detail.Instructors)
+	}
+}
+
+func TestParseClassDetailWithoutAvailability(t *testing.T) {
+	page := `<html><body>
+<span id="DERIVED_CLSRCH_DESCRLONG">Intro to programming.</span>
+<span id="DERIVED_CLSRCH_SSR_CLASSNOTE_LONG">Bring a laptop.</span>
+<table><tr><td>Class Capacity</td><td align="left">100</td></tr></table>
+</body></html>`
+	var component Component
+	_, err := parseClassDetailResponse(strings.NewReader(page), &component, true,
+		&warningCollector{})
+	if err == nil {
+		t.Error("expected an error for the unexpected availability layout")
+	}
+	if component.Detail == nil || component.Detail.Description != "Intro to programming." ||
+		component.Detail.Notes != "Bring a laptop." {
+		t.Error("detail should be parsed despite the availability error:", component.Detail)
+	}
+}
+
+func TestParseClassDetailBadReservedSeats(t *testing.T) {
+	page := strings.Replace(testClassDetailPage, "<td>20</td><td>18</td>",
+		"<td>TBA</td><td>18</td>", 1)
+	var component Component
+	_, err := parseClassDetailResponse(strings.NewReader(page), &component, false,
+		&warningCollector{})
+	if err == nil {
+		t.Error("strict mode should fail on bad reserved seats")
+	}
+
+	component = Component{Section: "001"}
+	warnings := &warningCollector{lenient: true}
+	open, err := parseClassDetailResponse(strings.NewReader(page), &component, false, warnings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	avail := component.ClassAvailability
+	if !open || avail == nil || avail.AvailableSeats != 5 || avail.ReservedSeats != nil {
+		t.Error("unexpected availability:", avail)
+	}
+	if len(warnings.warnings) != 1 || warnings.warnings[0].Field != "Reserved Seats" ||
+		warnings.warnings[0].Component != "001" {
+		t.Error("unexpected warnings:", warnings.warnings)
+	}
+}
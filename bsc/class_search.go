@@ -0,0 +1,538 @@
+package bsc
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var classSearchPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.CLASS_SEARCH.GBL"
+var guestClassSearchPath string = "/EMPLOYEE/HRMS/c/COMMUNITY_ACCESS.CLASS_SEARCH.GBL"
+
+const classSearchAction = "CLASS_SRCH_WRK2_SSR_PB_CLASS_SRCH"
+
+// FetchClassDetail looks up a single class by its class number and downloads its class detail
+// page.
+//
+// Unlike FetchSchedule, this does not depend on the position of the class in the user's schedule,
+// so it can be used to refresh the availability of one class without fetching everything else.
+// The returned Component has its ClassAvailability and Detail filled in.
+func (c *Client) FetchClassDetail(term Term, classNumber int) (*Component, error) {
+	form, values, err := c.startClassSearch(term)
+	if err != nil {
+		return nil, err
+	}
+
+	classNumberField, ok := form.fieldName("SSR_CLSRCH_WRK_CLASS_NBR$")
+	if !ok {
+		return nil, errors.New("could not find class number field")
+	}
+	values.Set(classNumberField, strconv.Itoa(classNumber))
+	form.setCheckbox(values, "SSR_CLSRCH_WRK_SSR_OPEN_ONLY$", false)
+
+	results, err := c.submitForm(form, values)
+	if err != nil {
+		return nil, err
+	}
+
+	detailAction, ok := findAction(results, "MTG_CLASS_NBR$")
+	if !ok {
+		if msg := pageErrorMessage(results); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, errors.New("class not found: " + strconv.Itoa(classNumber))
+	}
+	resultsForm, err := parsePSForm(results)
+	if err != nil {
+		return nil, err
+	}
+	detailPage, err := c.submitForm(resultsForm, resultsForm.submitValues(detailAction))
+	if err != nil {
+		return nil, err
+	}
+
+	component, err := parseClassDetailComponent(detailPage)
+	if err != nil {
+		return nil, err
+	}
+	component.ClassNumber = classNumber
+	component.Location = EngineParseLocation(c.uni, component.Room)
+	return component, nil
+}
+
+// startClassSearch loads the class search page and selects a term. It returns the search form and
+// the values to submit for the search, which the caller should fill in with search criteria.
+func (c *Client) startClassSearch(term Term) (*psForm, url.Values, error) {
+	root, err := c.fetchPage(c.publicPage(classSearchPath, guestClassSearchPath))
+	if err != nil {
+		return nil, nil, err
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := form.submitValues(classSearchAction)
+
+	if err := setTermSelect(form, values, "CLASS_SRCH_WRK2_STRM$", term); err != nil {
+		return nil, nil, err
+	}
+	return form, values, nil
+}
+
+// parseClassDetailComponent parses a class detail page into a Component, including its meeting
+// information, availability, and detail.
+func parseClassDetailComponent(root *html.Node) (*Component, error) {
+	// Class search does not report warnings, so reserved seats which cannot be parsed are left out.
+	var component Component
+	courseOpen, err := parseExtraComponentInfo(root, &component, &warningCollector{lenient: true})
+	if err != nil {
+		return nil, err
+	}
+	component.Open = &courseOpen
+	detail := parseClassDetail(root)
+	component.Detail = &detail
+
+	componentType, ok := scrape.Find(root, byIDPrefix("SSR_CLS_DTL_WRK_SSR_COMPONENT_LONG"))
+	if ok {
+		component.Type = ParseComponentType(nodeInnerText(componentType))
+	}
+
+	if table, ok := findTableWithHeader(root, "Days & Times"); ok {
+		grid, err := ParseGrid(table)
+		if err != nil {
+			return nil, err
+		}
+		if len(grid.Rows) > 0 {
+			meeting := grid.Maps()[0]
+			meeting["Start/End Date"] = meeting["Meeting Dates"]
+			meetingComponent, errs := parseComponentInfoMap(meeting)
+			for _, fieldErr := range errs {
+				if fieldErr.field != "Class Nbr" {
+					return nil, ParseWarning{
+						Field: fieldErr.field,
+						Value: fieldErr.value,
+						Err:   fieldErr.err,
+					}
+				}
+			}
+			component.WeeklyTimes = meetingComponent.WeeklyTimes
+			component.Room = meetingComponent.Room
+			component.Instructors = parseInstructorCell(grid.Cell(0, "Instructor"))
+			component.StartDate = meetingComponent.StartDate
+			component.EndDate = meetingComponent.EndDate
+		}
+	}
+
+	return &component, nil
+}
+
+// A CatalogNumberMatch specifies how a ClassQuery's CatalogNumber is compared to course numbers.
+type CatalogNumberMatch int
+
+const (
+	CatalogNumberExact CatalogNumberMatch = iota
+	CatalogNumberContains
+	CatalogNumberGreaterOrEqual
+	CatalogNumberLessOrEqual
+)
+
+// A ClassQuery specifies the criteria for SearchClasses. Empty fields are not used as criteria.
+//
+// Fields which are shown as drop-downs in the Student Center (Subject, Career, InstructionMode,
+// Attribute, and AttributeValue) may be given as either the code or the description, e.g. "CS" or
+// "Computer Science".
+type ClassQuery struct {
+	Subject            string
+	CatalogNumber      string
+	CatalogNumberMatch CatalogNumberMatch
+	Career             string
+
+	// OpenOnly limits the results to open classes.
+	OpenOnly bool
+
+	// Days limits the results to classes which only meet on the given days.
+	Days []time.Weekday
+
+	// StartAfter and EndBefore limit the results to classes which meet within a window of time.
+	// They are ignored if they are 0.
+	StartAfter TimeOfDay
+	EndBefore  TimeOfDay
+
+	// InstructorLastName limits the results to classes with an instructor whose last name begins
+	// with the given string.
+	InstructorLastName string
+
+	InstructionMode string
+
+	// Attribute and AttributeValue limit the results to classes with a course attribute, such as
+	// "Distribution Requirement" with the value "Physical Sciences". AttributeValue may be left
+	// empty to match any value of the attribute.
+	Attribute      string
+	AttributeValue string
+
+	// FetchAvailability indicates that each component's ClassAvailability should be filled in.
+	// This requires two extra requests per component.
+	FetchAvailability bool
+}
+
+var catalogNumberMatchValues = map[CatalogNumberMatch]string{
+	CatalogNumberExact:          "E",
+	CatalogNumberContains:       "C",
+	CatalogNumberGreaterOrEqual: "G",
+	CatalogNumberLessOrEqual:    "T",
+}
+
+var weekdayCheckboxes = map[time.Weekday]string{
+	time.Sunday:    "SSR_CLSRCH_WRK_SUN$",
+	time.Monday:    "SSR_CLSRCH_WRK_MON$",
+	time.Tuesday:   "SSR_CLSRCH_WRK_TUES$",
+	time.Wednesday: "SSR_CLSRCH_WRK_WED$",
+	time.Thursday:  "SSR_CLSRCH_WRK_THURS$",
+	time.Friday:    "SSR_CLSRCH_WRK_FRI$",
+	time.Saturday:  "SSR_CLSRCH_WRK_SAT$",
+}
+
+const (
+	classSearchBackAction    = "CLASS_SRCH_WRK2_SSR_PB_BACK"
+	classSearchConfirmAction = "#ICSave"
+
+	// maxClassSearchPages limits how many times SearchClasses expands a result grid, in case the
+	// "View All" links never disappear.
+	maxClassSearchPages = 100
+)
+
+// SearchClasses runs a class search for a term and returns the matching courses and their
+// components.
+//
+// Each component has its Open status filled in. Its ClassAvailability is only filled in if the
+// query's FetchAvailability is true.
+//
+// Class search is available to guest clients.
+func (c *Client) SearchClasses(term Term, query ClassQuery) ([]Course, error) {
+	form, values, err := c.startClassSearch(term)
+	if err != nil {
+		return nil, err
+	}
+	form, values, err = c.fillClassQuery(form, values, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := c.submitForm(form, values)
+	if err != nil {
+		return nil, err
+	}
+
+	// PeopleSoft asks for confirmation before showing more than 50 results.
+	if _, ok := findAction(results, "MTG_CLASS_NBR$"); !ok {
+		if _, ok := findAction(results, classSearchConfirmAction); ok {
+			confirmForm, err := parsePSForm(results)
+			if err != nil {
+				return nil, err
+			}
+			results, err = c.submitForm(confirmForm,
+				confirmForm.submitValues(classSearchConfirmAction))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, ok := findAction(results, "MTG_CLASS_NBR$"); !ok {
+		msg := pageErrorMessage(results)
+		if isNoResultsMessage(msg) {
+			return nil, nil
+		} else if msg == "" {
+			return nil, errors.New("unrecognized class search results page")
+		}
+		return nil, errors.New(msg)
+	}
+
+	results, err = c.expandClassSearchResults(results)
+	if err != nil {
+		return nil, err
+	}
+
+	courses, detailActions := parseClassSearchResults(results)
+	if query.FetchAvailability {
+		if err := c.fetchSearchAvailability(results, courses, detailActions); err != nil {
+			return nil, err
+		}
+	}
+	fillLocations(c.uni, courses)
+	return courses, nil
+}
+
+// isNoResultsMessage returns true if a page message says that a class search found nothing, as in
+// "The search returns no results that match the criteria specified."
+func isNoResultsMessage(msg string) bool {
+	return strings.Contains(strings.ToLower(msg), "no results")
+}
+
+// fillClassQuery sets the search criteria on a class search form. Since the course attribute
+// values depend on the attribute, this may submit the form to refresh it, in which case the new
+// form is returned.
+func (c *Client) fillClassQuery(form *psForm, values url.Values,
+	query ClassQuery) (*psForm, url.Values, error) {
+	if query.Subject != "" {
+		// Some universities show the subject as a drop-down and others as a text field.
+		var err error
+		if _, ok := form.fieldName("SSR_CLSRCH_WRK_SUBJECT_SRCH$"); ok {
+			err = form.setSelect(values, "SSR_CLSRCH_WRK_SUBJECT_SRCH$", query.Subject)
+		} else {
+			err = form.setField(values, "SSR_CLSRCH_WRK_SUBJECT$", query.Subject)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if query.CatalogNumber != "" {
+		matchValue := catalogNumberMatchValues[query.CatalogNumberMatch]
+		if err := form.setSelect(values, "SSR_CLSRCH_WRK_SSR_EXACT_MATCH1$",
+			matchValue); err != nil {
+			return nil, nil, err
+		}
+		err := form.setField(values, "SSR_CLSRCH_WRK_CATALOG_NBR$", query.CatalogNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if query.Career != "" {
+		if err := form.setSelect(values, "SSR_CLSRCH_WRK_ACAD_CAREER$", query.Career); err != nil {
+			return nil, nil, err
+		}
+	}
+	form.setCheckbox(values, "SSR_CLSRCH_WRK_SSR_OPEN_ONLY$", query.OpenOnly)
+
+	if len(query.Days) > 0 {
+		// "I" means "include only these days".
+		if err := form.setSelect(values, "SSR_CLSRCH_WRK_INCLUDE_CLASS_DAYS$", "I"); err != nil {
+			return nil, nil, err
+		}
+		for _, prefix := range weekdayCheckboxes {
+			form.setCheckbox(values, prefix, false)
+		}
+		for _, day := range query.Days {
+			if !form.setCheckbox(values, weekdayCheckboxes[day], true) {
+				return nil, nil, errors.New("could not find checkbox for " + day.String())
+			}
+		}
+	}
+
+	if query.StartAfter != 0 {
+		if err := form.setSelect(values, "SSR_CLSRCH_WRK_SSR_START_TIME_OPR$", "GE"); err != nil {
+			return nil, nil, err
+		}
+		err := form.setField(values, "SSR_CLSRCH_WRK_MEETING_TIME_START$",
+			query.StartAfter.String())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if query.EndBefore != 0 {
+		if err := form.setSelect(values, "SSR_CLSRCH_WRK_SSR_END_TIME_OPR$", "LE"); err != nil {
+			return nil, nil, err
+		}
+		err := form.setField(values, "SSR_CLSRCH_WRK_MEETING_TIME_END$", query.EndBefore.String())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if query.InstructorLastName != "" {
+		// "B" means "begins with".
+		if err := form.setSelect(values, "SSR_CLSRCH_WRK_SSR_EXACT_MATCH2$", "B"); err != nil {
+			return nil, nil, err
+		}
+		err := form.setField(values, "SSR_CLSRCH_WRK_LAST_NAME$", query.InstructorLastName)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if query.InstructionMode != "" {
+		err := form.setSelect(values, "SSR_CLSRCH_WRK_INSTRUCTION_MODE$", query.InstructionMode)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if query.Attribute != "" {
+		attrField, ok := form.fieldName("SSR_CLSRCH_WRK_CRSE_ATTR$")
+		if !ok {
+			return nil, nil, errors.New("could not find course attribute field")
+		}
+		if err := form.setSelect(values, attrField, query.Attribute); err != nil {
+			return nil, nil, err
+		}
+		if query.AttributeValue != "" {
+			// Changing the attribute refreshes the page with the attribute's values. PeopleSoft
+			// uses the field's name as the ICAction for field changes.
+			values.Set("ICAction", attrField)
+			refreshed, err := c.submitForm(form, values)
+			if err != nil {
+				return nil, nil, err
+			}
+			if form, err = parsePSForm(refreshed); err != nil {
+				return nil, nil, err
+			}
+			values = form.submitValues(classSearchAction)
+			if err := form.setSelect(values, "SSR_CLSRCH_WRK_CRSE_ATTR_VALUE$",
+				query.AttributeValue); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return form, values, nil
+}
+
+// expandClassSearchResults clicks each "View All" link on a page of search results, so that every
+// section of every course is shown.
+func (c *Client) expandClassSearchResults(root *html.Node) (*html.Node, error) {
+	for i := 0; i < maxClassSearchPages; i++ {
+		link, ok := scrape.Find(root, func(node *html.Node) bool {
+			return node.DataAtom == atom.A &&
+				strings.Contains(getNodeAttribute(node, "id"), "$hviewall$") &&
+				strings.TrimSpace(nodeInnerText(node)) == "View All"
+		})
+		if !ok {
+			return root, nil
+		}
+		form, err := parsePSForm(root)
+		if err != nil {
+			return nil, err
+		}
+		root, err = c.submitForm(form, form.submitValues(getNodeAttribute(link, "id")))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, errors.New("too many pages of search results")
+}
+
+// fetchSearchAvailability opens the class detail page for each component in a set of search
+// results and fills in its availability.
+func (c *Client) fetchSearchAvailability(results *html.Node, courses []Course,
+	detailActions []string) error {
+	var components []*Component
+	for i := range courses {
+		for j := range courses[i].Components {
+			components = append(components, &courses[i].Components[j])
+		}
+	}
+	for i, component := range components {
+		form, err := parsePSForm(results)
+		if err != nil {
+			return err
+		}
+		detailPage, err := c.submitForm(form, form.submitValues(detailActions[i]))
+		if err != nil {
+			return err
+		}
+		courseOpen, err := parseExtraComponentInfo(detailPage, component,
+			&warningCollector{lenient: true})
+		if err != nil {
+			return err
+		}
+		component.Open = &courseOpen
+
+		detailForm, err := parsePSForm(detailPage)
+		if err != nil {
+			return err
+		}
+		results, err = c.submitForm(detailForm, detailForm.submitValues(classSearchBackAction))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseClassSearchResults parses the courses on a class search results page. It also returns the
+// ICAction which opens the class detail page for each component, in the order that the components
+// appear.
+func parseClassSearchResults(root *html.Node) ([]Course, []string) {
+	var courses []Course
+	var detailActions []string
+
+	nodes := scrape.FindAll(root, func(node *html.Node) bool {
+		id := getNodeAttribute(node, "id")
+		return strings.HasPrefix(id, "win0divSSR_CLSRSLT_WRK_GROUPBOX2GP$") ||
+			(node.DataAtom == atom.A && strings.HasPrefix(id, "MTG_CLASS_NBR$"))
+	})
+	for _, node := range nodes {
+		id := getNodeAttribute(node, "id")
+		if strings.HasPrefix(id, "win0divSSR_CLSRSLT_WRK_GROUPBOX2GP$") {
+			title := strings.Join(strings.Fields(nodeInnerText(node)), " ")
+			department, number := parseCourseTitle(title)
+			courses = append(courses, Course{Name: title, Department: department,
+				Number: number})
+			continue
+		} else if len(courses) == 0 {
+			continue
+		}
+
+		index := strings.TrimPrefix(id, "MTG_CLASS_NBR$")
+		component := parseClassSearchRow(root, index)
+		component.ClassNumber, _ = strconv.Atoi(strings.TrimSpace(nodeInnerText(node)))
+
+		// A course is open if any of its components are open.
+		course := &courses[len(courses)-1]
+		course.Components = append(course.Components, component)
+		if component.Open != nil {
+			open := *component.Open || (course.Open != nil && *course.Open)
+			course.Open = &open
+		}
+		detailActions = append(detailActions, id)
+	}
+
+	return courses, detailActions
+}
+
+// parseClassSearchRow parses the fields of one section in the class search results. The index is
+// the suffix of the fields' ids.
+func parseClassSearchRow(root *html.Node, index string) Component {
+	var component Component
+
+	// The class name looks like "001-LEC(1234)", sometimes followed by a line like "Regular".
+	if node, ok := scrape.Find(root, scrape.ById("MTG_CLASSNAME$"+index)); ok {
+		if lines := nodeLines(node); len(lines) > 0 {
+			name := lines[0]
+			if parenIndex := strings.Index(name, "("); parenIndex >= 0 {
+				name = name[:parenIndex]
+			}
+			parts := strings.SplitN(name, "-", 2)
+			component.Section = strings.TrimSpace(parts[0])
+			if len(parts) == 2 {
+				component.Type = ParseComponentType(strings.TrimSpace(parts[1]))
+			}
+		}
+	}
+
+	if weeklyTimes, err := ParseWeeklyTimes(spanTextByID(root, "MTG_DAYTIME$"+index)); err == nil {
+		component.WeeklyTimes = *weeklyTimes
+	}
+	component.Room = spanTextByID(root, "MTG_ROOM$"+index)
+	component.Instructors = ParseInstructors(detailTextByID(root, "MTG_INSTR$"+index))
+	// Despite its name, the MTG_TOPIC field holds the meeting dates.
+	start, end, err := parseDateRange(spanTextByID(root, "MTG_TOPIC$"+index))
+	if err == nil {
+		component.StartDate, component.EndDate = start, end
+	}
+
+	if status, ok := scrape.Find(root,
+		scrape.ById("win0divDERIVED_CLSRCH_SSR_STATUS_LONG$"+index)); ok {
+		if img, ok := scrape.Find(status, scrape.ByTag(atom.Img)); ok {
+			open := getNodeAttribute(img, "alt") == "Open"
+			component.Open = &open
+		}
+	}
+
+	return component
+}
@@ -0,0 +1,166 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testClassSearchPage = `<html><body>
+<form name="win0" class="PSForm" method="post" action="CLASS_SEARCH.GBL">
+<input type="hidden" name="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" value="3">
+<select name="SSR_CLSRCH_WRK_SUBJECT_SRCH$0">
+<option value=""></option><option value="CS">Computer Science</option>
+</select>
+<select name="SSR_CLSRCH_WRK_SSR_EXACT_MATCH1$1">
+<option value="C">contains</option><option value="E">is exactly</option>
+<option value="G">greater than or equal to</option>
+<option value="T">less than or equal to</option>
+</select>
+<input type="text" name="SSR_CLSRCH_WRK_CATALOG_NBR$1" value="">
+<input type="hidden" name="SSR_CLSRCH_WRK_SSR_OPEN_ONLY$chk$3" value="Y">
+<input type="checkbox" name="SSR_CLSRCH_WRK_SSR_OPEN_ONLY$3" value="Y" checked>
+<select name="SSR_CLSRCH_WRK_INCLUDE_CLASS_DAYS$5">
+<option value="J">include any of these days</option>
+<option value="I">include only these days</option>
+</select>
+<input type="hidden" name="SSR_CLSRCH_WRK_MON$chk$5" value="N">
+<input type="checkbox" name="SSR_CLSRCH_WRK_MON$5" value="Y">
+<input type="hidden" name="SSR_CLSRCH_WRK_WED$chk$5" value="N">
+<input type="checkbox" name="SSR_CLSRCH_WRK_WED$5" value="Y">
+<select name="SSR_CLSRCH_WRK_SSR_START_TIME_OPR$4">
+<option value="GE">greater than or equal to</option>
+<option value="LE">less than or equal to</option>
+</select>
+<input type="text" name="SSR_CLSRCH_WRK_MEETING_TIME_START$4" value="">
+</form>
+</body></html>`
+
+func TestFillClassQuery(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testClassSearchPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := form.submitValues(classSearchAction)
+	query := ClassQuery{
+		Subject:            "Computer Science",
+		CatalogNumber:      "2000",
+		CatalogNumberMatch: CatalogNumberGreaterOrEqual,
+		Days:               []time.Weekday{time.Monday, time.Wednesday},
+		StartAfter:         10 * 60,
+	}
+	// Without an AttributeValue, fillClassQuery does not need to make any requests.
+	_, values, err = (*Client)(nil).fillClassQuery(form, values, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"SSR_CLSRCH_WRK_SUBJECT_SRCH$0":       "CS",
+		"SSR_CLSRCH_WRK_SSR_EXACT_MATCH1$1":   "G",
+		"SSR_CLSRCH_WRK_CATALOG_NBR$1":        "2000",
+		"SSR_CLSRCH_WRK_SSR_OPEN_ONLY$chk$3":  "N",
+		"SSR_CLSRCH_WRK_SSR_OPEN_ONLY$3":      "",
+		"SSR_CLSRCH_WRK_INCLUDE_CLASS_DAYS$5": "I",
+		"SSR_CLSRCH_WRK_MON$chk$5":            "Y",
+		"SSR_CLSRCH_WRK_WED$5":                "Y",
+		"SSR_CLSRCH_WRK_MEETING_TIME_START$4": "10:00AM",
+		"ICAction":                            classSearchAction,
+	}
+	for name, value := range expected {
+		if values.Get(name) != value {
+			t.Errorf("field %s should be %q but got %q", name, value, values.Get(name))
+		}
+	}
+
+	if _, _, err := (*Client)(nil).fillClassQuery(form, values,
+		ClassQuery{Subject: "Underwater Basket Weaving"}); err == nil {
+		t.Error("unknown subject should fail")
+	}
+}
+
+const testClassSearchResultsPage = `<html><body>
+<div id="win0divSSR_CLSRSLT_WRK_GROUPBOX2GP$0">CS&nbsp; 2110 - Object-Oriented Programming</div>
+<table>
+<tr><td><a id="MTG_CLASS_NBR$0" href="javascript:submitAction_win0('MTG_CLASS_NBR$0')">1234</a>
+</td>
+<td><span id="MTG_CLASSNAME$0">001-LEC<br>Regular</span></td>
+<td><span id="MTG_DAYTIME$0">MoWeFr 10:10AM - 11:00AM</span></td>
+<td><span id="MTG_ROOM$0">Olin Hall 155</span></td>
+<td><span id="MTG_INSTR$0">Jane Doe</span></td>
+<td><span id="MTG_TOPIC$0">08/27/2015 - 12/08/2015</span></td>
+<td><div id="win0divDERIVED_CLSRCH_SSR_STATUS_LONG$0"><img alt="Closed" src="x.gif"></div></td>
+</tr>
+<tr><td><a id="MTG_CLASS_NBR$1">1235</a></td>
+<td><span id="MTG_CLASSNAME$1">201-DIS(1235)</span></td>
+<td><span id="MTG_DAYTIME$1">TBA</span></td>
+<td><span id="MTG_ROOM$1">TBA</span></td>
+<td><span id="MTG_INSTR$1">Staff</span></td>
+<td><span id="MTG_TOPIC$1">08/27/2015 - 12/08/2015</span></td>
+<td><div id="win0divDERIVED_CLSRCH_SSR_STATUS_LONG$1"><img alt="Open" src="y.gif"></div></td>
+</tr>
+</table>
+<div id="win0divSSR_CLSRSLT_WRK_GROUPBOX2GP$1">MATH 2940 - Linear Algebra</div>
+<table><tr><td><a id="MTG_CLASS_NBR$2">2001</a></td>
+<td><span id="MTG_CLASSNAME$2">001-LEC</span></td>
+<td><div id="win0divDERIVED_CLSRCH_SSR_STATUS_LONG$2"><img alt="Wait List"></div></td>
+</tr></table>
+</body></html>`
+
+func TestParseClassSearchResults(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testClassSearchResultsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	courses, actions := parseClassSearchResults(root)
+	if len(courses) != 2 {
+		t.Fatal("expected 2 courses but got", len(courses))
+	}
+	if len(actions) != 3 || actions[2] != "MTG_CLASS_NBR$2" {
+		t.Error("unexpected detail actions:", actions)
+	}
+
+	cs := courses[0]
+	if cs.Department != "CS" || cs.Number != "2110" || len(cs.Components) != 2 {
+		t.Fatal("unexpected course:", cs)
+	}
+	if cs.Open == nil || !*cs.Open {
+		t.Error("course with an open section should be open")
+	}
+	lecture := cs.Components[0]
+	if lecture.ClassNumber != 1234 || lecture.Section != "001" ||
+		lecture.Type != ComponentTypeLecture || lecture.WeeklyTimes.Start != 10*60+10 ||
+		lecture.Room != "Olin Hall 155" || lecture.EndDate != (Date{time.December, 8, 2015}) {
+		t.Error("unexpected lecture:", lecture)
+	}
+	if lecture.Open == nil || *lecture.Open {
+		t.Error("lecture should be closed")
+	}
+	if len(lecture.Instructors) != 1 || lecture.Instructors[0].LastName != "Doe" {
+		t.Error("unexpected instructors:", lecture.Instructors)
+	}
+	discussion := cs.Components[1]
+	if discussion.Section != "201" || discussion.Type != ComponentTypeDiscussion {
+		t.Error("unexpected discussion:", discussion)
+	}
+
+	if courses[1].Open == nil || *courses[1].Open {
+		t.Error("waitlisted course should not be open")
+	}
+}
+
+func TestIsNoResultsMessage(t *testing.T) {
+	if !isNoResultsMessage("The search returns no results that match the criteria specified.") {
+		t.Error("expected a no results message")
+	}
+	for _, msg := range []string{"", "You are not authorized to access this component."} {
+		if isNoResultsMessage(msg) {
+			t.Errorf("%q is not a no results message", msg)
+		}
+	}
+}
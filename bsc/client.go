@@ -7,11 +7,16 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"sync"
-
-	"golang.org/x/net/html"
+	"time"
 )
 
 var redirectionRejectedError = errors.New("redirect occurred")
+
+// ErrLoginRequired is returned when a guest Client requests a page which requires a login.
+var ErrLoginRequired = errors.New("page requires a login")
+
+// ErrGuestUnsupported is returned by NewGuestClient for engines which are not GuestEngines.
+var ErrGuestUnsupported = errors.New("university engine does not support guests")
 var scheduleListViewPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_LIST.GBL?Page=SSR_SSENRL_LIST"
 
 // A Client makes requests to a University's Student Center.
@@ -24,6 +29,12 @@ type Client struct {
 	username string
 	password string
 	uni      UniversityEngine
+
+	// limiter is shared with any sessions cloned from this Client.
+	limiter *rateLimiter
+
+	// guest is true for clients created with NewGuestClient.
+	guest bool
 }
 
 // NewClient creates a new Client which authenticates with the supplied username, password, and
@@ -59,7 +70,46 @@ func NewClient(username, password string, uni UniversityEngine) *Client {
 		CheckRedirect: rejectRedirect,
 		Transport: transport,
 	}
-	return &Client{sync.RWMutex{}, httpClient, username, password, uni}
+	return &Client{
+		client:   httpClient,
+		username: username,
+		password: password,
+		uni:      uni,
+		limiter:  &rateLimiter{},
+	}
+}
+
+// NewGuestClient creates a Client which does not log in. Guest clients can only use the pages which
+// PeopleSoft makes public, namely class search (SearchClasses and FetchClassDetail) and the course
+// catalog. Other requests fail with ErrLoginRequired.
+//
+// The engine must be a GuestEngine, since PeopleSoft serves guest pages from a different root URL
+// than student pages. Otherwise, this fails with ErrGuestUnsupported. Of the built-in engines,
+// URIEngine supports guests.
+//
+// Since guest clients see the same data as everybody else, their results can be cached and shared
+// between users.
+func NewGuestClient(uni UniversityEngine) (*Client, error) {
+	if _, ok := uni.(GuestEngine); !ok {
+		return nil, ErrGuestUnsupported
+	}
+	c := NewClient("", "", uni)
+	c.guest = true
+	return c, nil
+}
+
+// Guest returns true if the Client was created with NewGuestClient.
+func (c *Client) Guest() bool {
+	return c.guest
+}
+
+// SetMinRequestInterval sets the minimum amount of time between requests to the Student Center.
+// By default, there is no limit.
+//
+// The limit applies to all of a Client's page requests, including those made by parallel
+// sessions. It does not apply to authentication.
+func (c *Client) SetMinRequestInterval(interval time.Duration) {
+	c.limiter.setInterval(interval)
 }
 
 // Authenticate authenticates with the university's server.
@@ -67,55 +117,57 @@ func NewClient(username, password string, uni UniversityEngine) *Client {
 // You should call this after creating a Client. However, if you do not, it will automatically be
 // called after the first request fails.
 func (c *Client) Authenticate() error {
+	if c.guest {
+		return ErrLoginRequired
+	}
 	c.authLock.Lock()
 	defer c.authLock.Unlock()
 	return c.uni.Authenticate(c)
 }
 
-// FetchCurrentSchedule downloads the user's current schedule.
+// FetchSchedule downloads the user's schedule for the first term listed in Student Center.
 //
 // If fetchMoreInfo is true, the components of each course will have extra information.
 func (c *Client) FetchSchedule(fetchMoreInfo bool) ([]Course, error) {
-	// TODO: GET page, then check if a <form> exists, then extract the name of the radio buttons?
-	postData := url.Values{}
-	postData.Add("SSR_DUMMY_RECV1$sels$0", "0")
-
-	if resp, err := c.RequestPagePost(scheduleListViewPath, postData); err != nil {
+	schedule, err := c.fetchSchedule(nil, ScheduleOptions{FetchMoreInfo: fetchMoreInfo})
+	if err != nil {
 		return nil, err
-	} else {
-		defer resp.Body.Close()
+	}
+	return schedule.Courses, nil
+}
 
-		contents, err := ioutil.ReadAll(resp.Body)
-		fmt.Println(string(contents))
+// FetchScheduleForTerm downloads the user's schedule for a specific term. The term should be one
+// of the terms returned by ListTerms.
+func (c *Client) FetchScheduleForTerm(term Term, opts ScheduleOptions) (*Schedule, error) {
+	return c.fetchSchedule(&term, opts)
+}
 
-		nodes, err := html.ParseFragment(resp.Body, nil)
-		if err != nil {
-			return nil, err
-		}
-		if len(nodes) != 1 {
-			return nil, errors.New("invalid number of root elements")
-		}
+func (c *Client) fetchSchedule(term *Term, opts ScheduleOptions) (*Schedule, error) {
+	root, selected, err := c.selectTerm(scheduleListViewPath, term)
+	if err != nil {
+		return nil, err
+	}
 
-		courses, err := parseSchedule(nodes[0])
-		if err != nil {
+	warnings := &warningCollector{lenient: opts.Lenient}
+	courses, err := parseSchedule(root, warnings)
+	if err != nil {
+		return nil, err
+	}
+	fillLocations(c.uni, courses)
+	if opts.FetchMoreInfo || opts.ClassDetail {
+		if err := c.fetchExtraScheduleInfo(*selected, courses, root, opts, warnings); err != nil {
 			return nil, err
 		}
-		if fetchMoreInfo {
-			c.authLock.RLock()
-			defer c.authLock.RUnlock()
-			if err := fetchExtraScheduleInfo(&c.client, courses, nodes[0]); err != nil {
-				return nil, err
-			}
-		}
-		return courses, nil
 	}
+	return &Schedule{Term: *selected, Courses: courses, Warnings: warnings.warnings}, nil
 }
 
 // RequestPage requests a page relative to the PeopleSoft root. This will automatically
 // re-authenticate if the session has timed out.
 // If the request fails for any reason (including a redirect), the returned response is nil.
 func (c *Client) RequestPage(page string) (*http.Response, error) {
-	requestURL := c.uni.RootURL() + page
+	requestURL := c.rootURL() + page
+	c.limiter.wait()
 	c.authLock.RLock()
 	resp, err := c.client.Get(requestURL)
 	c.authLock.RUnlock()
@@ -131,6 +183,7 @@ func (c *Client) RequestPage(page string) (*http.Response, error) {
 		return nil, err
 	}
 
+	c.limiter.wait()
 	c.authLock.RLock()
 	resp, err = c.client.Get(requestURL)
 	c.authLock.RUnlock()
@@ -145,7 +198,8 @@ func (c *Client) RequestPage(page string) (*http.Response, error) {
 }
 
 func (c *Client) RequestPagePost(page string, postData url.Values) (*http.Response, error) {
-	requestURL := c.uni.RootURL() + page
+	requestURL := c.rootURL() + page
+	c.limiter.wait()
 	c.authLock.RLock()
 	resp, err := c.client.PostForm(requestURL, postData)
 	c.authLock.RUnlock()
@@ -161,6 +215,7 @@ func (c *Client) RequestPagePost(page string, postData url.Values) (*http.Respon
 		return nil, err
 	}
 
+	c.limiter.wait()
 	c.authLock.RLock()
 	resp, err = c.client.Get(requestURL)
 	c.authLock.RUnlock()
@@ -174,6 +229,24 @@ func (c *Client) RequestPagePost(page string, postData url.Values) (*http.Respon
 	}
 }
 
+// rootURL returns the PeopleSoft root URL to use for requests. This is the engine's GuestRootURL
+// for guest clients.
+func (c *Client) rootURL() string {
+	if c.guest {
+		return c.uni.(GuestEngine).GuestRootURL()
+	}
+	return c.uni.RootURL()
+}
+
+// publicPage returns the path of a page which is available to both students and guests. PeopleSoft
+// serves the guest versions of pages from a separate component.
+func (c *Client) publicPage(studentPath, guestPath string) string {
+	if c.guest {
+		return guestPath
+	}
+	return studentPath
+}
+
 // postGenericLoginForm uses parseGenericLoginForm on the given page and POSTs the username and
 // password. It may fail at several points. If all is successful, it returns the result of the POST.
 //
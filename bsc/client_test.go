@@ -25,7 +25,26 @@ func TestAuthenticate(t *testing.T) {
 	}
 }
 
-func TestFetchCurrentSchedule(t *testing.T) {
+func TestListTerms(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover term listing")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	terms, err := c.ListTerms()
+	if err != nil {
+		t.Fatal("failed to list terms:", err)
+	} else if len(terms) == 0 {
+		t.Fatal("term list is empty")
+	}
+	if _, err := c.FetchScheduleForTerm(terms[len(terms)-1], ScheduleOptions{}); err != nil {
+		t.Error("failed to fetch schedule for term", terms[len(terms)-1], ":", err)
+	}
+}
+
+func TestFetchSchedule(t *testing.T) {
 	if testOfflineOnly {
 		t.Skip("offline tests do not cover course fetching")
 	}
@@ -33,13 +52,224 @@ func TestFetchCurrentSchedule(t *testing.T) {
 	if err := c.Authenticate(); err != nil {
 		t.Fatal("could not authenticate:", err)
 	}
-	if courses, err := c.FetchCurrentSchedule(false); err != nil {
+	if courses, err := c.FetchSchedule(false); err != nil {
 		t.Error("failed to fetch courses:", err)
 	} else if courses == nil || len(courses) == 0 {
 		t.Error("course list is empty or nil")
 	}
 }
 
+func TestFetchClassDetail(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover class detail fetching")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	terms, err := c.ListTerms()
+	if err != nil {
+		t.Fatal("failed to list terms:", err)
+	}
+	schedule, err := c.FetchScheduleForTerm(terms[0], ScheduleOptions{})
+	if err != nil {
+		t.Fatal("failed to fetch schedule:", err)
+	} else if len(schedule.Courses) == 0 || len(schedule.Courses[0].Components) == 0 {
+		t.Skip("schedule has no components to look up")
+	}
+	classNumber := schedule.Courses[0].Components[0].ClassNumber
+	component, err := c.FetchClassDetail(schedule.Term, classNumber)
+	if err != nil {
+		t.Fatal("failed to fetch class detail:", err)
+	}
+	if component.ClassAvailability == nil {
+		t.Error("class detail has no availability")
+	}
+}
+
+func TestSearchClasses(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover class searches")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	terms, err := c.ListTerms()
+	if err != nil {
+		t.Fatal("failed to list terms:", err)
+	}
+	schedule, err := c.FetchScheduleForTerm(terms[0], ScheduleOptions{})
+	if err != nil {
+		t.Fatal("failed to fetch schedule:", err)
+	} else if len(schedule.Courses) == 0 {
+		t.Skip("schedule has no courses to search for")
+	}
+	course := schedule.Courses[0]
+	results, err := c.SearchClasses(schedule.Term, ClassQuery{
+		Subject:       course.Department,
+		CatalogNumber: course.Number,
+	})
+	if err != nil {
+		t.Fatal("failed to search classes:", err)
+	}
+	if len(results) == 0 || results[0].Number != course.Number {
+		t.Error("search did not find", course.Name)
+	}
+}
+
+func TestListCatalogCourses(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover the course catalog")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	subjects, err := c.ListSubjects()
+	if err != nil {
+		t.Fatal("failed to list subjects:", err)
+	} else if len(subjects) == 0 {
+		t.Fatal("no subjects found")
+	}
+	courses, err := c.ListCatalogCourses(subjects[0].Code)
+	if err != nil {
+		t.Fatal("failed to list courses:", err)
+	} else if len(courses) == 0 {
+		t.Fatal("no courses found for", subjects[0].Code)
+	}
+	entry, err := c.FetchCatalogEntry(courses[0].ID())
+	if err != nil {
+		t.Fatal("failed to fetch catalog entry:", err)
+	}
+	if entry.Subject != subjects[0].Code || entry.Title == "" {
+		t.Error("unexpected catalog entry:", entry)
+	}
+}
+
+func TestFetchShoppingCart(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover the shopping cart")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	terms, err := c.ListTerms()
+	if err != nil {
+		t.Fatal("failed to list terms:", err)
+	}
+	if _, err := c.FetchShoppingCart(terms[0]); err != nil {
+		t.Error("failed to fetch shopping cart:", err)
+	}
+}
+
+type testGuestEngine struct {
+	CornellEngine
+}
+
+func (_ testGuestEngine) GuestRootURL() string {
+	return "https://example.com/psc/guest"
+}
+
+// testMinimalEngine implements only the required methods of UniversityEngine.
+type testMinimalEngine struct{}
+
+func (_ testMinimalEngine) Authenticate(client *Client) error {
+	return nil
+}
+
+func (_ testMinimalEngine) RootURL() string {
+	return "https://example.com/psc/student"
+}
+
+func TestFetchPlanner(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover the planner")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	if _, err := c.FetchPlanner(); err != nil {
+		t.Error("failed to fetch planner:", err)
+	}
+}
+
+func TestFetchGrades(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover grades")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	terms, err := c.ListTerms()
+	if err != nil {
+		t.Fatal("failed to list terms:", err)
+	}
+	if _, err := c.FetchGrades(terms[len(terms)-1]); err != nil {
+		t.Error("failed to fetch grades:", err)
+	}
+}
+
+func TestFetchUnofficialTranscript(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover the transcript")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	transcript, err := c.FetchUnofficialTranscript()
+	if err != nil {
+		t.Fatal("failed to fetch transcript:", err)
+	}
+	EngineGradeScale(testAuthEngine).Recompute(transcript)
+}
+
+func TestFetchAcademicRequirements(t *testing.T) {
+	if testOfflineOnly {
+		t.Skip("offline tests do not cover academic requirements")
+	}
+	c := NewClient(testAuthUsername, testAuthPassword, testAuthEngine)
+	if err := c.Authenticate(); err != nil {
+		t.Fatal("could not authenticate:", err)
+	}
+	if _, err := c.FetchAcademicRequirements(); err != nil {
+		t.Error("failed to fetch academic requirements:", err)
+	}
+}
+
+func TestGuestClient(t *testing.T) {
+	if _, err := NewGuestClient(CornellEngine{}); err != ErrGuestUnsupported {
+		t.Error("engines without guest support should fail with ErrGuestUnsupported but got", err)
+	}
+
+	c, err := NewGuestClient(testGuestEngine{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Guest() {
+		t.Error("guest client should report that it is a guest")
+	}
+	if err := c.Authenticate(); err != ErrLoginRequired {
+		t.Error("guest authentication should fail with ErrLoginRequired but got", err)
+	}
+	if c.publicPage(classSearchPath, guestClassSearchPath) != guestClassSearchPath {
+		t.Error("guest client should use the guest class search")
+	}
+	if c.rootURL() != "https://example.com/psc/guest" {
+		t.Error("unexpected guest root URL:", c.rootURL())
+	}
+	if NewClient("user", "pass", testGuestEngine{}).rootURL() != cornellRootURL {
+		t.Error("students should not use the guest root URL")
+	}
+	if c, err := NewGuestClient(URIEngine{}); err != nil || c.rootURL() != uriGuestRootURL {
+		t.Error("URI should support guests but got", err)
+	}
+}
+
 func TestMain(m *testing.M) {
 	if os.Getenv("BSC_TEST_OFFLINE") != "" {
 		testOfflineOnly = true
@@ -1,6 +1,9 @@
 package bsc
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var cornellAuthURL string = "https://css.adminapps.cornell.edu/psc/cuselfservice/" +
 	"EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSS_STUDENT_CENTER.GBL?" +
@@ -51,6 +54,27 @@ func (_ CornellEngine) Authenticate(client *Client) error {
 	return nil
 }
 
+// ParseLocation parses Cornell's room text, which gives full building names like "Olin Hall 155".
+func (_ CornellEngine) ParseLocation(room string) Location {
+	loc := ParseLocation(room)
+	if !loc.TBA && !loc.Online {
+		loc.Campus = "Ithaca"
+	}
+	return loc
+}
+
+// TimeZone returns the time zone of Cornell's Ithaca campus.
+func (_ CornellEngine) TimeZone() *time.Location {
+	return easternTime()
+}
+
+// GradeScale returns Cornell's grade scale, in which an A+ is worth 4.3 points.
+func (_ CornellEngine) GradeScale() GradeScale {
+	scale := StandardGradeScale()
+	scale["A+"] = 4.3
+	return scale
+}
+
 // RootURL returns the root URL of Cornell's Student Center.
 func (_ CornellEngine) RootURL() string {
 	return cornellRootURL
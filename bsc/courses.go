@@ -20,6 +20,11 @@ type Course struct {
 	// Open indicates the status (i.e. "openness") of this course. If this is nil, it means that the
 	// status of this course is unknown.
 	Open *bool
+
+	// WaitlistPosition is the user's position on the wait list for a course with the status
+	// EnrollmentStatusWaitlisted. It is 0 if the user is not on the wait list or the position is
+	// not shown.
+	WaitlistPosition int
 }
 
 // A Component is one component of a course. Components have meeting times, locations, and
@@ -29,14 +34,27 @@ type Component struct {
 	Section     string
 	Type        ComponentType
 	WeeklyTimes WeeklyTimes
-	Instructors []string
+	Instructors []Instructor
 	Room        string
+	Location    Location
 	StartDate   Date
 	EndDate     Date
 
+	// Open indicates whether or not the class is open. If this is nil, the status is unknown.
+	Open *bool
+
 	// ClassAvailability indicates the space available in the class. It may be nil if it was not
 	// requested explicitly.
 	ClassAvailability *ClassAvailability
+
+	// Detail contains the descriptive information from the class detail page. It may be nil if it
+	// was not requested explicitly.
+	Detail *ClassDetail
+
+	// sectionAction is the ICAction of the component's link on the schedule list view, which
+	// opens its class detail page. It is empty for components which did not come from the
+	// schedule.
+	sectionAction string
 }
 
 // A ComponentType represents the type of a Component. This may be, for example,
@@ -62,6 +80,13 @@ func ParseComponentType(str string) ComponentType {
 		"Laboratory": ComponentTypeLab,
 		"Seminar":    ComponentTypeSeminar,
 		"Recitation": ComponentTypeRecitation,
+
+		// The class search results use abbreviations.
+		"LEC": ComponentTypeLecture,
+		"DIS": ComponentTypeDiscussion,
+		"LAB": ComponentTypeLab,
+		"SEM": ComponentTypeSeminar,
+		"REC": ComponentTypeRecitation,
 	}
 	if ct, ok := mapping[str]; ok {
 		return ct
@@ -306,6 +331,32 @@ func (d Date) String() string {
 	return monthStr + "/" + dayStr + "/" + yearStr
 }
 
+// Time returns the midnight at the start of the date in the given location.
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Weekday returns the day of the week on which the date falls.
+func (d Date) Weekday() time.Weekday {
+	return d.Time(time.UTC).Weekday()
+}
+
+// AddDays returns the date which is a given number of days after this one. The number of days may
+// be negative.
+func (d Date) AddDays(days int) Date {
+	return DateFromTime(d.Time(time.UTC).AddDate(0, 0, days))
+}
+
+// Before returns true if d comes before another date.
+func (d Date) Before(other Date) bool {
+	return d.Time(time.UTC).Before(other.Time(time.UTC))
+}
+
+// DateFromTime returns the Date on which a time falls, in the time's location.
+func DateFromTime(t time.Time) Date {
+	return Date{Month: t.Month(), Day: t.Day(), Year: t.Year()}
+}
+
 // ClassAvailability stores various information about space available in a class.
 type ClassAvailability struct {
 	Capacity         int
@@ -313,4 +364,12 @@ type ClassAvailability struct {
 	AvailableSeats   int
 	WaitListCapacity int
 	WaitListTotal    int
+
+	// ReservedSeats lists the seats which are held for particular groups of students, such as
+	// majors in the department. Reserved seats are included in Capacity and EnrollmentTotal.
+	ReservedSeats []ReservedCapacity
+
+	// Combined is the availability across all of the sections combined with this class. It is nil
+	// if the class is not combined with other sections.
+	Combined *CombinedAvailability
 }
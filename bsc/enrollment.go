@@ -0,0 +1,449 @@
+package bsc
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var dropPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_DROP.GBL" +
+	"?Page=SSR_SSENRL_DROP"
+var swapPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_SWAP.GBL" +
+	"?Page=SSR_SSENRL_SWAP"
+var editEnrollmentPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_EDIT.GBL" +
+	"?Page=SSR_SSENRL_EDIT"
+
+const (
+	enrollAction       = "DERIVED_REGFRM1_LINK_ADD_ENRL$"
+	dropAction         = "DERIVED_REGFRM1_LINK_DROP_ENRL$"
+	finishAction       = "DERIVED_REGFRM1_SSR_PB_SUBMIT"
+	editProceedAction  = "DERIVED_REGFRM1_SSR_PB_GO$"
+	cartSelectField    = "P_SELECT$"
+	dropSelectField    = "DERIVED_REGFRM1_SSR_SELECT$"
+	enrolledClassField = "DERIVED_REGFRM1_DESCR50$"
+)
+
+// An EnrollmentOutcome is the result of an enrollment action for one class.
+type EnrollmentOutcome int
+
+const (
+	EnrollmentOutcomeUnknown EnrollmentOutcome = iota
+
+	// EnrollmentOutcomePending is used for dry runs, which stop before anything is changed.
+	EnrollmentOutcomePending
+
+	EnrollmentOutcomeSuccess
+	EnrollmentOutcomeWaitlisted
+	EnrollmentOutcomeTimeConflict
+	EnrollmentOutcomePrerequisiteNotMet
+	EnrollmentOutcomeUnitLimitExceeded
+	EnrollmentOutcomeClassClosed
+
+	// EnrollmentOutcomeError is used for errors which do not fit another outcome. The result's
+	// Message describes the error.
+	EnrollmentOutcomeError
+)
+
+// String returns a human-readable version of the EnrollmentOutcome.
+func (e EnrollmentOutcome) String() string {
+	names := map[EnrollmentOutcome]string{
+		EnrollmentOutcomePending:            "Pending",
+		EnrollmentOutcomeSuccess:            "Success",
+		EnrollmentOutcomeWaitlisted:         "Waitlisted",
+		EnrollmentOutcomeTimeConflict:       "Time Conflict",
+		EnrollmentOutcomePrerequisiteNotMet: "Prerequisite Not Met",
+		EnrollmentOutcomeUnitLimitExceeded:  "Unit Limit Exceeded",
+		EnrollmentOutcomeClassClosed:        "Class Closed",
+		EnrollmentOutcomeError:              "Error",
+	}
+	if name, ok := names[e]; ok {
+		return name
+	} else {
+		return "Unknown"
+	}
+}
+
+// An EnrollmentResult is the result of an enrollment action for one class.
+type EnrollmentResult struct {
+	// Class is the class as PeopleSoft describes it, e.g. "CS 2110".
+	Class string
+
+	// ClassNumber is 0 if the class number was not shown.
+	ClassNumber int
+
+	Message string
+	Outcome EnrollmentOutcome
+}
+
+// An EnrollmentReport is the result of Enroll, Drop, Swap, or EditEnrollment.
+type EnrollmentReport struct {
+	// DryRun is true if the action stopped at the confirmation step. In this case, every result
+	// has the outcome EnrollmentOutcomePending.
+	DryRun bool
+
+	Results []EnrollmentResult
+}
+
+// Succeeded returns true if every class was enrolled, dropped, or changed successfully. This is
+// false for dry runs.
+func (r *EnrollmentReport) Succeeded() bool {
+	if r.DryRun || len(r.Results) == 0 {
+		return false
+	}
+	for _, result := range r.Results {
+		if result.Outcome != EnrollmentOutcomeSuccess {
+			return false
+		}
+	}
+	return true
+}
+
+// Enroll enrolls in classes from the shopping cart. The classes must already be in the cart (see
+// AddToCart). Nothing is submitted unless the confirmation page lists exactly the given classes.
+//
+// If dryRun is true, this stops at the confirmation step and returns the classes which would be
+// enrolled, without changing anything.
+func (c *Client) Enroll(term Term, classNumbers []int, dryRun bool) (*EnrollmentReport, error) {
+	root, _, err := c.selectTerm(shoppingCartPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	root, err = c.selectClassesAndSubmit(root, cartSelectField, enrollAction, classNumbers)
+	if err != nil {
+		return nil, err
+	}
+	return c.finishEnrollment(root, dryRun, classNumbers, nil)
+}
+
+// Drop drops enrolled classes. Dropping a class may not be reversible, so callers should consider
+// doing a dry run first. Nothing is submitted unless the confirmation page lists exactly the given
+// classes.
+//
+// If dryRun is true, this stops at the confirmation step and returns the classes which would be
+// dropped, without changing anything.
+func (c *Client) Drop(term Term, classNumbers []int, dryRun bool) (*EnrollmentReport, error) {
+	root, _, err := c.selectTerm(dropPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	root, err = c.selectClassesAndSubmit(root, dropSelectField, dropAction, classNumbers)
+	if err != nil {
+		return nil, err
+	}
+	return c.finishEnrollment(root, dryRun, classNumbers, nil)
+}
+
+// Swap drops an enrolled class and enrolls in another class in its place. The enrolled class is
+// only dropped if the enrollment in the new class succeeds.
+//
+// If dryRun is true, this stops at the confirmation step without changing anything.
+func (c *Client) Swap(term Term, dropClassNumber, addClassNumber int, options CartOptions,
+	dryRun bool) (*EnrollmentReport, error) {
+	root, _, err := c.selectTerm(swapPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	addAction, ok := findAction(root, cartAddAction)
+	if !ok {
+		return nil, errors.New("could not find the button to choose a class")
+	}
+	values := form.submitValues(addAction)
+	if err := selectEnrolledClass(form, values, dropClassNumber); err != nil {
+		return nil, err
+	}
+	err = form.setField(values, "DERIVED_REGFRM1_CLASS_NBR", strconv.Itoa(addClassNumber))
+	if err != nil {
+		return nil, err
+	}
+	if root, err = c.submitForm(form, values); err != nil {
+		return nil, err
+	}
+	if root, err = c.fillClassOptions(root, options); err != nil {
+		return nil, err
+	}
+	// The confirmation page may list the dropped class and related components along with the
+	// new class.
+	allowed := append([]int{dropClassNumber}, options.RelatedClassNumbers...)
+	return c.finishEnrollment(root, dryRun, []int{addClassNumber}, allowed)
+}
+
+// EditEnrollment changes the options of an enrolled class, such as its grading basis or related
+// components.
+//
+// If dryRun is true, this stops at the confirmation step without changing anything.
+func (c *Client) EditEnrollment(term Term, classNumber int, options CartOptions,
+	dryRun bool) (*EnrollmentReport, error) {
+	root, _, err := c.selectTerm(editEnrollmentPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	proceedAction, ok := findAction(root, editProceedAction)
+	if !ok {
+		return nil, errors.New("could not find the button to edit a class")
+	}
+	values := form.submitValues(proceedAction)
+	if err := selectEnrolledClass(form, values, classNumber); err != nil {
+		return nil, err
+	}
+	if root, err = c.submitForm(form, values); err != nil {
+		return nil, err
+	}
+	if root, err = c.fillClassOptions(root, options); err != nil {
+		return nil, err
+	}
+	return c.finishEnrollment(root, dryRun, []int{classNumber}, options.RelatedClassNumbers)
+}
+
+// selectClassesAndSubmit checks the rows of a class list for the given class numbers and triggers
+// an action, such as enrolling in or dropping the checked classes.
+func (c *Client) selectClassesAndSubmit(root *html.Node, checkboxField, action string,
+	classNumbers []int) (*html.Node, error) {
+	if len(classNumbers) == 0 {
+		return nil, errors.New("no classes given")
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	actionID, ok := findAction(root, action)
+	if !ok {
+		if msg := pageErrorMessage(root); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, errors.New("could not find action: " + strings.TrimSuffix(action, "$"))
+	}
+	values := form.submitValues(actionID)
+	if err := selectClassRows(root, form, values, checkboxField, classNumbers); err != nil {
+		return nil, err
+	}
+	return c.submitForm(form, values)
+}
+
+// selectClassRows checks the rows of a class list for the given class numbers and unchecks every
+// other row, including rows which PeopleSoft rendered as checked.
+func selectClassRows(root *html.Node, form *psForm, values url.Values, checkboxField string,
+	classNumbers []int) error {
+	form.setCheckbox(values, checkboxField, false)
+	rows := classCheckboxes(root, checkboxField)
+	for _, classNumber := range classNumbers {
+		name, ok := rows[classNumber]
+		if !ok {
+			return errors.New("class is not listed: " + strconv.Itoa(classNumber))
+		}
+		checkRowCheckbox(values, name)
+	}
+	return nil
+}
+
+// finishEnrollment clicks the "Finish" button on a confirmation page and parses the results. If
+// dryRun is true, it parses the confirmation page instead.
+//
+// Since the action cannot be undone, the confirmation page is checked first: it must list every
+// required class and no classes other than the allowed ones.
+func (c *Client) finishEnrollment(root *html.Node, dryRun bool, required,
+	allowed []int) (*EnrollmentReport, error) {
+	finish, ok := findAction(root, finishAction)
+	if !ok {
+		if msg := pageErrorMessage(root); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, errors.New("did not reach the confirmation step")
+	}
+
+	pending, err := parseEnrollmentResults(root, true)
+	if err != nil {
+		return nil, err
+	}
+	confirmation := &EnrollmentReport{DryRun: true, Results: pending}
+	if err := confirmation.CheckClasses(required, allowed); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return confirmation, nil
+	}
+
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	resultPage, err := c.submitForm(form, form.submitValues(finish))
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseEnrollmentResults(resultPage, false)
+	if err != nil {
+		if msg := pageErrorMessage(resultPage); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return &EnrollmentReport{Results: results}, nil
+}
+
+// CheckClasses returns an error unless the report lists every class in required and no classes
+// other than those in required and allowed. Results without a class number are not allowed, since
+// they cannot be identified.
+func (r *EnrollmentReport) CheckClasses(required, allowed []int) error {
+	permitted := map[int]bool{}
+	for _, classNumber := range append(append([]int{}, required...), allowed...) {
+		permitted[classNumber] = true
+	}
+	listed := map[int]bool{}
+	for _, result := range r.Results {
+		if result.ClassNumber == 0 {
+			return errors.New("unidentified class listed: " + result.Class)
+		} else if !permitted[result.ClassNumber] {
+			return errors.New("unexpected class listed: " + strconv.Itoa(result.ClassNumber))
+		}
+		listed[result.ClassNumber] = true
+	}
+	for _, classNumber := range required {
+		if !listed[classNumber] {
+			return errors.New("class not listed: " + strconv.Itoa(classNumber))
+		}
+	}
+	return nil
+}
+
+// classCheckboxes maps class numbers to the names of the checkboxes in their rows. The checkboxes
+// are found by their name prefix, and the class number is found in parentheses in their rows.
+func classCheckboxes(root *html.Node, field string) map[int]string {
+	res := map[int]string{}
+	checkboxes := scrape.FindAll(root, func(node *html.Node) bool {
+		name := getNodeAttribute(node, "name")
+		return node.DataAtom == atom.Input &&
+			strings.ToLower(getNodeAttribute(node, "type")) == "checkbox" &&
+			strings.HasPrefix(name, field) && !strings.Contains(name, "$chk$")
+	})
+	for _, checkbox := range checkboxes {
+		row := checkbox.Parent
+		for row != nil && row.DataAtom != atom.Tr {
+			row = row.Parent
+		}
+		if row == nil {
+			continue
+		}
+		match := classNumberInParens.FindStringSubmatch(nodeInnerText(row))
+		if match == nil {
+			continue
+		}
+		classNumber, _ := strconv.Atoi(match[1])
+		res[classNumber] = getNodeAttribute(checkbox, "name")
+	}
+	return res
+}
+
+// checkRowCheckbox checks a checkbox like "FIELD$3" along with its hidden "FIELD$chk$3" field.
+func checkRowCheckbox(values url.Values, name string) {
+	values[name] = []string{"Y"}
+	if dollarIndex := strings.LastIndex(name, "$"); dollarIndex >= 0 {
+		chkName := name[:dollarIndex] + "$chk" + name[dollarIndex:]
+		values[chkName] = []string{"Y"}
+	}
+}
+
+// selectEnrolledClass chooses an enrolled class from the drop-down on the swap and edit pages. The
+// options look like "CS 2110-001 Lecture (1234)".
+func selectEnrolledClass(form *psForm, values url.Values, classNumber int) error {
+	name, ok := form.fieldName(enrolledClassField)
+	if !ok {
+		return errors.New("could not find the enrolled class field")
+	}
+	numStr := strconv.Itoa(classNumber)
+	for _, option := range form.options[name] {
+		if option.value == numStr || strings.Contains(option.text, "("+numStr+")") {
+			values[name] = []string{option.value}
+			return nil
+		}
+	}
+	return errors.New("not enrolled in class: " + numStr)
+}
+
+// parseEnrollmentResults parses the table of classes on a confirmation or results page. On a
+// results page, each class has a message and a status icon.
+func parseEnrollmentResults(root *html.Node, pending bool) ([]EnrollmentResult, error) {
+	header := "Message"
+	if pending {
+		header = "Class"
+	}
+	table, ok := findTableWithHeader(root, header)
+	if !ok {
+		return nil, errors.New("could not find the table of classes")
+	}
+	grid, err := ParseGrid(table)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []EnrollmentResult
+	for i := range grid.Rows {
+		classCell := grid.Cell(i, "Class")
+		if classCell == nil || classCell.Text == "" {
+			continue
+		}
+		result := EnrollmentResult{Outcome: EnrollmentOutcomePending}
+		if len(classCell.Lines) > 0 {
+			result.Class = classCell.Lines[0]
+		}
+		if match := classNumberInParens.FindStringSubmatch(classCell.Text); match != nil {
+			result.ClassNumber, _ = strconv.Atoi(match[1])
+		}
+		if !pending {
+			if cell := grid.Cell(i, "Message"); cell != nil {
+				result.Message = cell.Text
+			}
+			status := ""
+			if cell := grid.Cell(i, "Status"); cell != nil && cell.Node != nil {
+				if img, ok := scrape.Find(cell.Node, scrape.ByTag(atom.Img)); ok {
+					status = getNodeAttribute(img, "alt")
+				}
+			}
+			result.Outcome = ParseEnrollmentOutcome(status, result.Message)
+		}
+		res = append(res, result)
+	}
+	return res, nil
+}
+
+// ParseEnrollmentOutcome determines the outcome of an enrollment action from the alternate text
+// of its status icon (e.g. "Success" or "Error") and its message.
+func ParseEnrollmentOutcome(status, message string) EnrollmentOutcome {
+	lowerMessage := strings.ToLower(message)
+	lowerStatus := strings.ToLower(status)
+	switch {
+	case strings.Contains(lowerMessage, "wait list") || strings.Contains(lowerMessage, "waitlist"):
+		if lowerStatus == "error" {
+			return EnrollmentOutcomeError
+		}
+		return EnrollmentOutcomeWaitlisted
+	case lowerStatus == "success":
+		return EnrollmentOutcomeSuccess
+	case strings.Contains(lowerMessage, "time conflict"):
+		return EnrollmentOutcomeTimeConflict
+	case strings.Contains(lowerMessage, "requisite"):
+		return EnrollmentOutcomePrerequisiteNotMet
+	case strings.Contains(lowerMessage, "unit") && (strings.Contains(lowerMessage, "maximum") ||
+		strings.Contains(lowerMessage, "limit") || strings.Contains(lowerMessage, "exceed")):
+		return EnrollmentOutcomeUnitLimitExceeded
+	case strings.Contains(lowerMessage, "class is full") ||
+		strings.Contains(lowerMessage, "closed"):
+		return EnrollmentOutcomeClassClosed
+	case lowerStatus == "error":
+		return EnrollmentOutcomeError
+	default:
+		return EnrollmentOutcomeUnknown
+	}
+}
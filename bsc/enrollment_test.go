@@ -0,0 +1,182 @@
+package bsc
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testDropPage = `<html><body>
+<form class="PSForm" action="SSR_SSENRL_DROP.GBL">
+<input type="hidden" name="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" value="2">
+<table>
+<tr><th>Select</th><th>Class</th><th>Description</th></tr>
+<tr><td><input type="hidden" name="DERIVED_REGFRM1_SSR_SELECT$chk$0" value="N">
+<input type="checkbox" name="DERIVED_REGFRM1_SSR_SELECT$0" value="Y"></td>
+<td>CS 2110-001<br>(1234)</td><td>OO Programming (Lecture)</td></tr>
+<tr><td><input type="hidden" name="DERIVED_REGFRM1_SSR_SELECT$chk$1" value="N">
+<input type="checkbox" name="DERIVED_REGFRM1_SSR_SELECT$1" value="Y"></td>
+<td>MATH 2940-002<br>(2001)</td><td>Linear Algebra (Lecture)</td></tr>
+</table>
+<select name="DERIVED_REGFRM1_DESCR50$225$">
+<option value=""></option>
+<option value="1">CS 2110-001 Lecture (1234)</option>
+<option value="2">MATH 2940-002 Lecture (2001)</option>
+</select>
+</form>
+</body></html>`
+
+func TestSelectEnrolledClasses(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testDropPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := classCheckboxes(root, dropSelectField)
+	if len(rows) != 2 || rows[2001] != "DERIVED_REGFRM1_SSR_SELECT$1" {
+		t.Fatal("unexpected checkboxes:", rows)
+	}
+	values := url.Values{}
+	checkRowCheckbox(values, rows[2001])
+	if values.Get("DERIVED_REGFRM1_SSR_SELECT$chk$1") != "Y" ||
+		values.Get("DERIVED_REGFRM1_SSR_SELECT$1") != "Y" {
+		t.Error("unexpected values:", values)
+	}
+
+	form, err := parsePSForm(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := selectEnrolledClass(form, values, 2001); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("DERIVED_REGFRM1_DESCR50$225$") != "2" {
+		t.Error("unexpected enrolled class value:", values.Get("DERIVED_REGFRM1_DESCR50$225$"))
+	}
+	if err := selectEnrolledClass(form, values, 9999); err == nil {
+		t.Error("selecting an unknown class should fail")
+	}
+}
+
+func TestSelectClassRowsUnchecksOthers(t *testing.T) {
+	// PeopleSoft may render a row as already checked, e.g. after a previous visit to the page.
+	page := strings.Replace(testDropPage,
+		`<input type="hidden" name="DERIVED_REGFRM1_SSR_SELECT$chk$0" value="N">
+<input type="checkbox" name="DERIVED_REGFRM1_SSR_SELECT$0" value="Y">`,
+		`<input type="hidden" name="DERIVED_REGFRM1_SSR_SELECT$chk$0" value="Y">
+<input type="checkbox" name="DERIVED_REGFRM1_SSR_SELECT$0" value="Y" checked>`, 1)
+	root, err := parseHTMLDocument(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.values.Get("DERIVED_REGFRM1_SSR_SELECT$0") != "Y" {
+		t.Fatal("the first row should start out checked")
+	}
+	values := form.submitValues("DERIVED_REGFRM1_LINK_DROP_ENRL$")
+	if err := selectClassRows(root, form, values, dropSelectField, []int{2001}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := values["DERIVED_REGFRM1_SSR_SELECT$0"]; ok ||
+		values.Get("DERIVED_REGFRM1_SSR_SELECT$chk$0") != "N" {
+		t.Error("pre-checked row was not unchecked:", values)
+	}
+	if values.Get("DERIVED_REGFRM1_SSR_SELECT$1") != "Y" ||
+		values.Get("DERIVED_REGFRM1_SSR_SELECT$chk$1") != "Y" {
+		t.Error("requested row was not checked:", values)
+	}
+	if err := selectClassRows(root, form, values, dropSelectField, []int{9999}); err == nil {
+		t.Error("selecting an unlisted class should fail")
+	}
+}
+
+func TestEnrollmentReportCheckClasses(t *testing.T) {
+	report := EnrollmentReport{DryRun: true, Results: []EnrollmentResult{
+		{Class: "CS 2110-001", ClassNumber: 1234},
+		{Class: "MATH 2940-002", ClassNumber: 2001},
+	}}
+	if err := report.CheckClasses([]int{1234, 2001}, nil); err != nil {
+		t.Error("exact match should pass:", err)
+	}
+	if err := report.CheckClasses([]int{1234}, nil); err == nil {
+		t.Error("an extra class should fail")
+	}
+	if err := report.CheckClasses([]int{1234}, []int{2001}); err != nil {
+		t.Error("an allowed extra class should pass:", err)
+	}
+	if err := report.CheckClasses([]int{1234, 2001, 3000}, nil); err == nil {
+		t.Error("a missing class should fail")
+	}
+	report.Results = append(report.Results, EnrollmentResult{Class: "PE 1100"})
+	if err := report.CheckClasses([]int{1234, 2001}, nil); err == nil {
+		t.Error("an unidentified class should fail")
+	}
+}
+
+const testEnrollmentResultsPage = `<html><body><table>
+<tr><th>Class</th><th>Message</th><th>Status</th></tr>
+<tr><td>CS 2110</td><td>Success: This class has been added to your schedule.</td>
+<td><img alt="Success"></td></tr>
+<tr><td>MATH 2940</td><td>Error: Unable to add class - time conflict with CS 2110.</td>
+<td><img alt="Error"></td></tr>
+<tr><td>CS 4820</td><td>Error: Unable to add class - requisites have not been met.</td>
+<td><img alt="Error"></td></tr>
+</table></body></html>`
+
+func TestParseEnrollmentResults(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testEnrollmentResultsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := parseEnrollmentResults(root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []EnrollmentOutcome{EnrollmentOutcomeSuccess, EnrollmentOutcomeTimeConflict,
+		EnrollmentOutcomePrerequisiteNotMet}
+	if len(results) != len(expected) {
+		t.Fatal("expected", len(expected), "results but got", len(results))
+	}
+	for i, outcome := range expected {
+		if results[i].Outcome != outcome {
+			t.Error("result", i, "should be", outcome, "but got", results[i].Outcome)
+		}
+	}
+	if results[0].Class != "CS 2110" {
+		t.Error("unexpected class:", results[0].Class)
+	}
+	pending, err := parseEnrollmentResults(root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 3 || pending[1].Outcome != EnrollmentOutcomePending {
+		t.Error("unexpected pending results:", pending)
+	}
+
+	report := EnrollmentReport{Results: results}
+	if report.Succeeded() {
+		t.Error("report with errors should not succeed")
+	}
+}
+
+func TestParseEnrollmentOutcome(t *testing.T) {
+	cases := []struct {
+		status   string
+		message  string
+		expected EnrollmentOutcome
+	}{
+		{"Success", "This class has been added to your wait list.", EnrollmentOutcomeWaitlisted},
+		{"Error", "Unable to add class - unit limit exceeded.", EnrollmentOutcomeUnitLimitExceeded},
+		{"Error", "Unable to add class - class is full.", EnrollmentOutcomeClassClosed},
+		{"Error", "Something unexpected happened.", EnrollmentOutcomeError},
+		{"", "", EnrollmentOutcomeUnknown},
+	}
+	for _, c := range cases {
+		if outcome := ParseEnrollmentOutcome(c.status, c.message); outcome != c.expected {
+			t.Errorf("%q should be %s but got %s", c.message, c.expected, outcome)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package bsc
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var examSchedulePath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_EXAM_L.GBL"
+
+// An Exam is a final exam for one of the user's classes.
+type Exam struct {
+	// ClassNumber is the class number of the examined component. It is 0 if the exam schedule does
+	// not list class numbers.
+	ClassNumber int
+
+	Department  string
+	Number      string
+	Section     string
+	Description string
+
+	// Date is the zero Date if the exam has not been scheduled yet. In this case, Start and End
+	// are also zero.
+	Date  Date
+	Start TimeOfDay
+	End   TimeOfDay
+	Room  string
+
+	// Course and Component are set by LinkExams. They are nil if the exam has not been linked or
+	// if no matching component was found.
+	Course    *Course
+	Component *Component
+}
+
+// Scheduled returns true if the exam has a known date.
+func (e *Exam) Scheduled() bool {
+	return e.Date != Date{}
+}
+
+// FetchExamSchedule downloads the user's final exam schedule for a term.
+func (c *Client) FetchExamSchedule(term Term) ([]Exam, error) {
+	root, _, err := c.selectTerm(examSchedulePath, &term)
+	if err != nil {
+		return nil, err
+	}
+	return parseExamSchedule(root)
+}
+
+// parseExamSchedule parses the exam table on the exam schedule page.
+func parseExamSchedule(root *html.Node) ([]Exam, error) {
+	table, ok := findTableWithHeader(root, "Exam Date")
+	if !ok {
+		return nil, errors.New("could not find exam schedule table")
+	}
+	rows, err := tableEntriesAsMaps(table)
+	if err != nil {
+		return nil, err
+	}
+
+	exams := make([]Exam, 0, len(rows))
+	for _, row := range rows {
+		exam, err := parseExamRow(row)
+		if err != nil {
+			return nil, err
+		}
+		exams = append(exams, exam)
+	}
+	return exams, nil
+}
+
+// parseExamRow processes a row of the exam schedule table.
+func parseExamRow(row map[string]string) (exam Exam, err error) {
+	if nbr := row["Class Nbr"]; nbr != "" {
+		if exam.ClassNumber, err = strconv.Atoi(nbr); err != nil {
+			return
+		}
+	}
+
+	// The class column looks like "CS 2110-001" or "CS 2110 - 001".
+	classParts := strings.SplitN(row["Class"], "-", 2)
+	if courseFields := strings.Fields(classParts[0]); len(courseFields) == 2 {
+		exam.Department, exam.Number = courseFields[0], courseFields[1]
+	} else {
+		return exam, errors.New("invalid class: " + row["Class"])
+	}
+	if len(classParts) == 2 {
+		exam.Section = strings.TrimSpace(classParts[1])
+	}
+
+	exam.Description = row["Description"]
+	exam.Room = row["Location"]
+	if exam.Room == "" {
+		exam.Room = row["Room"]
+	}
+
+	if dateStr := row["Exam Date"]; dateStr != "" && dateStr != "TBA" {
+		if exam.Date, err = ParseDate(dateStr); err != nil {
+			return
+		}
+		timeParts := strings.Split(row["Exam Time"], " - ")
+		if len(timeParts) != 2 {
+			return exam, errors.New("invalid exam time: " + row["Exam Time"])
+		}
+		if exam.Start, err = ParseTimeOfDay(strings.TrimSpace(timeParts[0])); err != nil {
+			return
+		}
+		if exam.End, err = ParseTimeOfDay(strings.TrimSpace(timeParts[1])); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// LinkExams sets the Course and Component of each exam to the matching entries in courses.
+//
+// Exams are matched by class number when the exam schedule provides one, and by department, number,
+// and section otherwise.
+func LinkExams(exams []Exam, courses []Course) {
+	for i := range exams {
+		exam := &exams[i]
+		exam.Course, exam.Component = nil, nil
+		for courseIndex := range courses {
+			course := &courses[courseIndex]
+			for componentIndex := range course.Components {
+				component := &course.Components[componentIndex]
+				if exam.Component == nil && exam.matchesComponent(course, component) {
+					exam.Course, exam.Component = course, component
+				}
+			}
+		}
+	}
+}
+
+func (e *Exam) matchesComponent(course *Course, component *Component) bool {
+	if e.ClassNumber != 0 {
+		return e.ClassNumber == component.ClassNumber
+	}
+	return e.Department == course.Department && e.Number == course.Number &&
+		e.Section == component.Section
+}
+
+// An ExamConflictKind is the type of an ExamConflict.
+type ExamConflictKind int
+
+const (
+	// ExamConflictOverlap indicates that two exams take place at the same time.
+	ExamConflictOverlap ExamConflictKind = iota
+
+	// ExamConflictThreeInADay indicates that three or more exams take place on the same day.
+	ExamConflictThreeInADay
+)
+
+// String returns a human-readable version of the ExamConflictKind.
+func (e ExamConflictKind) String() string {
+	names := map[ExamConflictKind]string{
+		ExamConflictOverlap:     "Overlap",
+		ExamConflictThreeInADay: "Three in a day",
+	}
+	if name, ok := names[e]; ok {
+		return name
+	} else {
+		return "Other"
+	}
+}
+
+// An ExamConflict is a set of exams which conflict with each other. Many universities allow
+// students to reschedule one of the exams in such a conflict.
+type ExamConflict struct {
+	Kind  ExamConflictKind
+	Date  Date
+	Exams []Exam
+}
+
+// FindExamConflicts finds overlapping exams and days with three or more exams. Unscheduled exams
+// are ignored.
+func FindExamConflicts(exams []Exam) []ExamConflict {
+	byDate := map[Date][]Exam{}
+	var dates []Date
+	for _, exam := range exams {
+		if !exam.Scheduled() {
+			continue
+		}
+		if _, ok := byDate[exam.Date]; !ok {
+			dates = append(dates, exam.Date)
+		}
+		byDate[exam.Date] = append(byDate[exam.Date], exam)
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	var res []ExamConflict
+	for _, date := range dates {
+		dayExams := byDate[date]
+		for i := 0; i < len(dayExams); i++ {
+			for j := i + 1; j < len(dayExams); j++ {
+				e1, e2 := dayExams[i], dayExams[j]
+				if e1.Start < e2.End && e2.Start < e1.End {
+					res = append(res, ExamConflict{
+						Kind:  ExamConflictOverlap,
+						Date:  date,
+						Exams: []Exam{e1, e2},
+					})
+				}
+			}
+		}
+		if len(dayExams) >= 3 {
+			res = append(res, ExamConflict{
+				Kind:  ExamConflictThreeInADay,
+				Date:  date,
+				Exams: dayExams,
+			})
+		}
+	}
+	return res
+}
@@ -0,0 +1,54 @@
+package bsc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindExamConflicts(t *testing.T) {
+	day1 := Date{time.December, 12, 2015}
+	day2 := Date{time.December, 14, 2015}
+	exams := []Exam{
+		{Department: "CS", Number: "2110", Date: day1, Start: 9 * 60, End: 11*60 + 30},
+		{Department: "MATH", Number: "2940", Date: day1, Start: 11 * 60, End: 13 * 60},
+		{Department: "PHYS", Number: "2213", Date: day1, Start: 19 * 60, End: 21*60 + 30},
+		{Department: "ECON", Number: "1110", Date: day2, Start: 9 * 60, End: 11*60 + 30},
+		{Department: "ENGL", Number: "1170"},
+	}
+	conflicts := FindExamConflicts(exams)
+	if len(conflicts) != 2 {
+		t.Fatal("expected 2 conflicts but got", len(conflicts))
+	}
+	if conflicts[0].Kind != ExamConflictOverlap || conflicts[0].Exams[0].Department != "CS" ||
+		conflicts[0].Exams[1].Department != "MATH" {
+		t.Error("unexpected overlap conflict:", conflicts[0])
+	}
+	if conflicts[1].Kind != ExamConflictThreeInADay || len(conflicts[1].Exams) != 3 ||
+		conflicts[1].Date != day1 {
+		t.Error("unexpected three-in-a-day conflict:", conflicts[1])
+	}
+}
+
+func TestLinkExams(t *testing.T) {
+	courses := []Course{
+		{Department: "CS", Number: "2110", Components: []Component{
+			{ClassNumber: 1234, Section: "001"},
+			{ClassNumber: 1235, Section: "201"},
+		}},
+	}
+	exams := []Exam{
+		{ClassNumber: 1234},
+		{Department: "CS", Number: "2110", Section: "201"},
+		{ClassNumber: 9999},
+	}
+	LinkExams(exams, courses)
+	if exams[0].Component != &courses[0].Components[0] {
+		t.Error("exam 0 not linked by class number")
+	}
+	if exams[1].Component != &courses[0].Components[1] || exams[1].Course != &courses[0] {
+		t.Error("exam 1 not linked by section")
+	}
+	if exams[2].Component != nil {
+		t.Error("exam 2 should not be linked")
+	}
+}
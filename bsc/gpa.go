@@ -0,0 +1,150 @@
+package bsc
+
+import "math"
+
+// gpaTolerance is the largest difference between a reported and a recomputed value which is not
+// considered a discrepancy. Transcripts round grade points and GPAs to three decimal places.
+const gpaTolerance = 0.005
+
+// A GradeScale maps letter grades to the grade points they are worth per unit. Grades which are
+// not in the scale (e.g. "S", "W", or "INC") do not count towards the GPA.
+type GradeScale map[string]float64
+
+// StandardGradeScale returns the common 4.0 scale, which has no A+.
+func StandardGradeScale() GradeScale {
+	return GradeScale{
+		"A": 4.0, "A-": 3.7,
+		"B+": 3.3, "B": 3.0, "B-": 2.7,
+		"C+": 2.3, "C": 2.0, "C-": 1.7,
+		"D+": 1.3, "D": 1.0, "D-": 0.7,
+		"F": 0,
+	}
+}
+
+// A GPADiscrepancy is a value on a Transcript which does not match the value computed from the
+// transcript's courses.
+type GPADiscrepancy struct {
+	// Term is the description of the term in which the discrepancy occurs.
+	Term string
+
+	// Course is the course whose grade points do not match its grade. It is nil if the
+	// discrepancy is in the term or cumulative statistics.
+	Course *TranscriptCourse
+
+	// Cumulative is true if the discrepancy is in the cumulative statistics shown with the term.
+	Cumulative bool
+
+	// Field is "Grade Points", "GPA Units", or "GPA".
+	Field    string
+	Reported float64
+	Computed float64
+}
+
+// A GPACalculation is the result of recomputing a Transcript's statistics.
+type GPACalculation struct {
+	// Terms contains the computed statistics for each of the transcript's terms, in order.
+	Terms      []GradeStatistics
+	Cumulative GradeStatistics
+
+	Discrepancies []GPADiscrepancy
+}
+
+// Recompute computes the term and cumulative statistics of a transcript from its courses and
+// compares them with the statistics printed on the transcript.
+//
+// A course counts towards the GPA if its grade is in the scale and it is not a repeat which is
+// excluded from the GPA. Transfer credit does not count towards the GPA, but it is included in the
+// cumulative units taken and passed.
+func (s GradeScale) Recompute(transcript *Transcript) *GPACalculation {
+	res := &GPACalculation{}
+	for _, credit := range transcript.TransferCredit {
+		for _, course := range credit.Courses {
+			res.Cumulative.UnitsTaken += course.Attempted
+			res.Cumulative.UnitsPassed += course.Earned
+		}
+	}
+
+	for termIndex := range transcript.Terms {
+		term := &transcript.Terms[termIndex]
+		var stats GradeStatistics
+		for courseIndex := range term.Courses {
+			course := &term.Courses[courseIndex]
+			stats.UnitsTaken += course.Attempted
+			stats.UnitsPassed += course.Earned
+
+			value, ok := s[course.Grade]
+			if !ok || course.RepeatStatus == RepeatExcluded {
+				continue
+			}
+			points := value * course.Attempted
+			stats.GPAUnits += course.Attempted
+			stats.GradePoints += points
+			if differs(course.GradePoints, points) {
+				res.Discrepancies = append(res.Discrepancies, GPADiscrepancy{
+					Term:     term.Term.Description,
+					Course:   course,
+					Field:    "Grade Points",
+					Reported: course.GradePoints,
+					Computed: points,
+				})
+			}
+		}
+		stats.GPA = computeGPA(stats)
+
+		res.Cumulative.UnitsTaken += stats.UnitsTaken
+		res.Cumulative.UnitsPassed += stats.UnitsPassed
+		res.Cumulative.GPAUnits += stats.GPAUnits
+		res.Cumulative.GradePoints += stats.GradePoints
+		res.Cumulative.GPA = computeGPA(res.Cumulative)
+		res.Terms = append(res.Terms, stats)
+
+		if term.Statistics != nil {
+			res.Discrepancies = append(res.Discrepancies,
+				compareStatistics(term.Term.Description, false, *term.Statistics, stats)...)
+		}
+		if term.Cumulative != nil {
+			res.Discrepancies = append(res.Discrepancies,
+				compareStatistics(term.Term.Description, true, *term.Cumulative,
+					res.Cumulative)...)
+		}
+	}
+	return res
+}
+
+// compareStatistics returns the discrepancies between reported and computed statistics. Units
+// taken and passed are not compared, since universities disagree on which courses they include.
+func compareStatistics(term string, cumulative bool, reported,
+	computed GradeStatistics) []GPADiscrepancy {
+	fields := []struct {
+		name               string
+		reported, computed float64
+	}{
+		{"Grade Points", reported.GradePoints, computed.GradePoints},
+		{"GPA Units", reported.GPAUnits, computed.GPAUnits},
+		{"GPA", reported.GPA, computed.GPA},
+	}
+	var res []GPADiscrepancy
+	for _, field := range fields {
+		if differs(field.reported, field.computed) {
+			res = append(res, GPADiscrepancy{
+				Term:       term,
+				Cumulative: cumulative,
+				Field:      field.name,
+				Reported:   field.reported,
+				Computed:   field.computed,
+			})
+		}
+	}
+	return res
+}
+
+func computeGPA(stats GradeStatistics) float64 {
+	if stats.GPAUnits == 0 {
+		return 0
+	}
+	return stats.GradePoints / stats.GPAUnits
+}
+
+func differs(reported, computed float64) bool {
+	return math.Abs(reported-computed) > gpaTolerance
+}
@@ -0,0 +1,231 @@
+package bsc
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var gradesPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSENRL_GRADE.GBL"
+
+// A Grade is the user's final grade in one class.
+type Grade struct {
+	// ClassNumber is 0 if the grades page does not list class numbers.
+	ClassNumber int
+
+	Department  string
+	Number      string
+	Section     string
+	Description string
+
+	Units        float64
+	GradingBasis string
+
+	// Grade is the letter grade (e.g. "A-" or "S"). It is empty if no grade has been posted.
+	Grade string
+
+	GradePoints float64
+}
+
+// Posted returns true if a grade has been posted for the class.
+func (g *Grade) Posted() bool {
+	return g.Grade != ""
+}
+
+// Matches returns true if the grade is for the given course. If the grade has a class number, the
+// course must have a component with that number. Otherwise, department and number are compared.
+func (g *Grade) Matches(course Course) bool {
+	if g.ClassNumber != 0 {
+		for _, component := range course.Components {
+			if component.ClassNumber == g.ClassNumber {
+				return true
+			}
+		}
+		if len(course.Components) > 0 {
+			return false
+		}
+	}
+	return g.Department == course.Department && g.Number == course.Number
+}
+
+// GradeStatistics are the unit and GPA totals shown below the grades for a term.
+type GradeStatistics struct {
+	UnitsTaken  float64
+	UnitsPassed float64
+
+	// GPAUnits is the number of units which count towards the GPA.
+	GPAUnits    float64
+	GradePoints float64
+	GPA         float64
+}
+
+// TermGrades contains the user's grades for a term.
+type TermGrades struct {
+	Term   Term
+	Grades []Grade
+
+	// TermStatistics and Cumulative are nil if the page does not show them, which happens before
+	// any grades have been posted.
+	TermStatistics *GradeStatistics
+	Cumulative     *GradeStatistics
+}
+
+// FetchGrades downloads the user's grades for a term.
+func (c *Client) FetchGrades(term Term) (*TermGrades, error) {
+	root, selected, err := c.selectTerm(gradesPath, &term)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := parseGrades(root)
+	if err != nil {
+		return nil, err
+	}
+	grades.Term = *selected
+	return grades, nil
+}
+
+// parseGrades parses the grade table and the statistics table on the grades page.
+func parseGrades(root *html.Node) (*TermGrades, error) {
+	table, ok := findTableWithHeader(root, "Grade")
+	if !ok {
+		return nil, errors.New("could not find grade table")
+	}
+	rows, err := tableEntriesAsMaps(table)
+	if err != nil {
+		return nil, err
+	}
+	var res TermGrades
+	for _, row := range rows {
+		if row["Class"] == "" {
+			continue
+		}
+		grade, err := parseGradeRow(row)
+		if err != nil {
+			return nil, err
+		}
+		res.Grades = append(res.Grades, grade)
+	}
+
+	if table, ok := findStatisticsTable(root); ok {
+		grid, err := ParseGrid(table)
+		if err != nil {
+			return nil, err
+		}
+		for i, row := range grid.Rows {
+			label := strings.ToLower(row[0].Text)
+			var stats *GradeStatistics
+			if strings.HasPrefix(label, "cum") {
+				res.Cumulative = &GradeStatistics{}
+				stats = res.Cumulative
+			} else if strings.HasPrefix(label, "term") {
+				res.TermStatistics = &GradeStatistics{}
+				stats = res.TermStatistics
+			} else {
+				continue
+			}
+			if err := parseGradeStatistics(grid, i, stats); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &res, nil
+}
+
+// parseGradeRow processes a row of the grade table.
+func parseGradeRow(row map[string]string) (grade Grade, err error) {
+	if nbr := row["Class Nbr"]; nbr != "" {
+		if grade.ClassNumber, err = strconv.Atoi(nbr); err != nil {
+			return
+		}
+	}
+
+	// Like the exam schedule, the class column may include a section (e.g. "CS 2110-001").
+	classParts := strings.SplitN(row["Class"], "-", 2)
+	if courseFields := strings.Fields(classParts[0]); len(courseFields) == 2 {
+		grade.Department, grade.Number = courseFields[0], courseFields[1]
+	} else {
+		return grade, errors.New("invalid class: " + row["Class"])
+	}
+	if len(classParts) == 2 {
+		grade.Section = strings.TrimSpace(classParts[1])
+	}
+
+	grade.Description = row["Description"]
+	grade.GradingBasis = row["Grading"]
+	grade.Grade = row["Grade"]
+
+	numbers := []struct {
+		header string
+		value  *float64
+	}{
+		{"Units", &grade.Units},
+		{"Grade Points", &grade.GradePoints},
+	}
+	for _, n := range numbers {
+		if str := row[n.header]; str != "" {
+			if *n.value, err = strconv.ParseFloat(str, 64); err != nil {
+				return grade, errors.New("invalid " + n.header + ": " + str)
+			}
+		}
+	}
+	return
+}
+
+// findStatisticsTable finds the table whose rows are labeled "Term" and "Cumulative".
+func findStatisticsTable(root *html.Node) (*html.Node, bool) {
+	return scrape.Find(root, func(node *html.Node) bool {
+		if node.DataAtom != atom.Table {
+			return false
+		}
+		for _, row := range tableRows(node) {
+			cells := rowCells(row)
+			if len(cells) > 1 {
+				label := strings.ToLower(strings.TrimSpace(nodeInnerText(cells[0])))
+				if strings.HasPrefix(label, "cumulative") {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// parseGradeStatistics reads a row of the statistics table. Universities label the columns
+// differently (e.g. "Units Taken" or "Taken"), so columns are identified by keywords.
+func parseGradeStatistics(grid *Grid, row int, stats *GradeStatistics) error {
+	for col, header := range grid.Headers {
+		var value *float64
+		lower := strings.ToLower(header)
+		// Any column mentioning the GPA, other than the GPA itself, holds GPA units. These are
+		// checked first, since they may be labeled "Units Taken Toward GPA" or "Taken for GPA".
+		switch {
+		case lower != "gpa" && strings.Contains(lower, "gpa"):
+			value = &stats.GPAUnits
+		case strings.Contains(lower, "taken"):
+			value = &stats.UnitsTaken
+		case strings.Contains(lower, "passed"):
+			value = &stats.UnitsPassed
+		case strings.Contains(lower, "points"):
+			value = &stats.GradePoints
+		case lower == "gpa":
+			value = &stats.GPA
+		default:
+			continue
+		}
+		str := grid.Rows[row][col].Text
+		if str == "" {
+			continue
+		}
+		num, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return errors.New("invalid " + header + ": " + str)
+		}
+		*value = num
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testGradesPage = `<html><body>
+<table>
+<tr><th>Class</th><th>Description</th><th>Units</th><th>Grading</th><th>Grade</th>
+<th>Grade Points</th></tr>
+<tr><td>CS 2110</td><td>Object-Oriented Programming</td><td>3.00</td><td>Graded</td>
+<td>A-</td><td>11.100</td></tr>
+<tr><td>PE 1100</td><td>Rock Climbing</td><td>1.00</td><td>Sat/Unsat</td><td>S</td>
+<td>0.000</td></tr>
+<tr><td>MATH 2940</td><td>Linear Algebra</td><td>4.00</td><td>Graded</td><td></td>
+<td></td></tr>
+</table>
+<table>
+<tr><th></th><th>Units Taken</th><th>Units Passed</th><th>GPA Units</th><th>Grade Points</th>
+<th>GPA</th></tr>
+<tr><td>Term</td><td>8.00</td><td>4.00</td><td>3.00</td><td>11.100</td><td>3.700</td></tr>
+<tr><td>Cumulative</td><td>38.00</td><td>34.00</td><td>30.00</td><td>105.000</td>
+<td>3.500</td></tr>
+</table>
+</body></html>`
+
+func TestParseGrades(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testGradesPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	grades, err := parseGrades(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grades.Grades) != 3 {
+		t.Fatal("expected 3 grades but got", len(grades.Grades))
+	}
+	first := grades.Grades[0]
+	if first.Department != "CS" || first.Number != "2110" || first.Grade != "A-" ||
+		first.Units != 3 || first.GradePoints != 11.1 || first.GradingBasis != "Graded" {
+		t.Error("unexpected first grade:", first)
+	}
+	if grades.Grades[2].Posted() {
+		t.Error("grade should not be posted:", grades.Grades[2])
+	}
+	if !first.Matches(Course{Department: "CS", Number: "2110"}) ||
+		first.Matches(Course{Department: "CS", Number: "3110"}) {
+		t.Error("grade matched the wrong course")
+	}
+
+	if grades.TermStatistics == nil || grades.Cumulative == nil {
+		t.Fatal("missing statistics")
+	}
+	expectedTerm := GradeStatistics{UnitsTaken: 8, UnitsPassed: 4, GPAUnits: 3,
+		GradePoints: 11.1, GPA: 3.7}
+	if *grades.TermStatistics != expectedTerm {
+		t.Error("unexpected term statistics:", *grades.TermStatistics)
+	}
+	if grades.Cumulative.GPA != 3.5 || grades.Cumulative.UnitsTaken != 38 {
+		t.Error("unexpected cumulative statistics:", *grades.Cumulative)
+	}
+}
+
+func TestParseGradeStatisticsTowardGPA(t *testing.T) {
+	for _, header := range []string{"Units Taken Toward GPA", "Taken for GPA", "GPA Units"} {
+		root, err := parseHTMLDocument(strings.NewReader(`<html><body><table>
+<tr><th></th><th>Units Taken</th><th>` + header + `</th><th>Grade Points</th><th>GPA</th></tr>
+<tr><td>Term</td><td>8.00</td><td>7.00</td><td>24.300</td><td>3.471</td></tr>
+<tr><td>Cumulative</td><td>16.00</td><td>15.00</td><td>52.500</td><td>3.500</td></tr>
+</table></body></html>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		table, ok := findStatisticsTable(root)
+		if !ok {
+			t.Fatal("could not find statistics table")
+		}
+		grid, err := ParseGrid(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stats GradeStatistics
+		if err := parseGradeStatistics(grid, 0, &stats); err != nil {
+			t.Fatal(err)
+		}
+		expected := GradeStatistics{UnitsTaken: 8, GPAUnits: 7, GradePoints: 24.3, GPA: 3.471}
+		if stats != expected {
+			t.Error(header, "expected", expected, "but got", stats)
+		}
+	}
+}
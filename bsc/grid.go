@@ -0,0 +1,249 @@
+package bsc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// A Grid is a rectangular model of an HTML table.
+//
+// Cells which span several rows or columns appear at every position they cover, so every row of a
+// Grid has the same number of cells. Only the table's own rows are included; the rows of nested
+// tables are not.
+type Grid struct {
+	// Headers contains the text of each column's heading. If the table has several rows of
+	// headings (e.g. a colspan heading above more specific ones), the lowest non-empty heading for
+	// each column is used.
+	Headers []string
+
+	// Rows contains the non-heading rows of the table.
+	Rows [][]*GridCell
+}
+
+// A GridCell is a <td> or <th> element from a Grid.
+type GridCell struct {
+	// Text is the trimmed inner text of the cell.
+	Text string
+
+	// Lines is the text of the cell split at <br> elements.
+	Lines []string
+
+	// Links contains the <a> elements in the cell.
+	Links []GridLink
+
+	// Attributes maps lower-case attribute names to their values.
+	Attributes map[string]string
+
+	// Row and Column give the position in the Grid's Rows of the top-left corner of the cell. For
+	// a cell which spans multiple positions, this indicates where the cell originated.
+	Row    int
+	Column int
+
+	RowSpan int
+	ColSpan int
+
+	Node *html.Node
+}
+
+// A GridLink is a link inside a GridCell.
+type GridLink struct {
+	Text string
+	Href string
+
+	// ID is the link's id attribute. For PeopleSoft links, this is the ICAction which the link
+	// triggers.
+	ID string
+}
+
+// ParseGrid builds a Grid from a <table> element.
+//
+// Leading rows consisting entirely of <th> elements are treated as headings. Columns whose headings
+// are hidden (e.g. with "display: none") are left out of the grid.
+func ParseGrid(table *html.Node) (*Grid, error) {
+	if table.DataAtom != atom.Table {
+		return nil, errors.New("grid must be a <table>")
+	}
+
+	rows := tableRows(table)
+	headerRowCount := 0
+	for _, row := range rows {
+		cells := rowCells(row)
+		if len(cells) == 0 || !allHeaderCells(cells) {
+			break
+		}
+		headerRowCount++
+	}
+
+	matrix := layoutTableCells(rows)
+	width := 0
+	for _, row := range matrix {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for i, row := range matrix {
+		for len(row) < width {
+			row = append(row, &GridCell{Row: i, Column: len(row), RowSpan: 1, ColSpan: 1,
+				Attributes: map[string]string{}})
+		}
+		matrix[i] = row
+	}
+
+	visibleColumns := make([]int, 0, width)
+	for col := 0; col < width; col++ {
+		hidden := false
+		for _, row := range matrix[:headerRowCount] {
+			if isHiddenCell(row[col].Node) {
+				hidden = true
+			}
+		}
+		if !hidden {
+			visibleColumns = append(visibleColumns, col)
+		}
+	}
+
+	grid := &Grid{Headers: make([]string, len(visibleColumns))}
+	for i, col := range visibleColumns {
+		for _, row := range matrix[:headerRowCount] {
+			if text := row[col].Text; text != "" {
+				grid.Headers[i] = text
+			}
+		}
+	}
+	positioned := map[*GridCell]bool{}
+	for rowIndex, row := range matrix[headerRowCount:] {
+		gridRow := make([]*GridCell, len(visibleColumns))
+		for i, col := range visibleColumns {
+			cell := row[col]
+			if !positioned[cell] {
+				positioned[cell] = true
+				cell.Row, cell.Column = rowIndex, i
+			}
+			gridRow[i] = cell
+		}
+		grid.Rows = append(grid.Rows, gridRow)
+	}
+
+	return grid, nil
+}
+
+// Column returns the index of the first column with the given heading, or -1 if there is none.
+func (g *Grid) Column(header string) int {
+	for i, h := range g.Headers {
+		if h == header {
+			return i
+		}
+	}
+	return -1
+}
+
+// Cell returns the cell in the given row under the given heading, or nil if there is no such
+// heading.
+func (g *Grid) Cell(row int, header string) *GridCell {
+	col := g.Column(header)
+	if col < 0 {
+		return nil
+	}
+	return g.Rows[row][col]
+}
+
+// Maps returns the text of each row, keyed by column heading.
+func (g *Grid) Maps() []map[string]string {
+	res := make([]map[string]string, len(g.Rows))
+	for i, row := range g.Rows {
+		m := map[string]string{}
+		for col, cell := range row {
+			if _, ok := m[g.Headers[col]]; !ok {
+				m[g.Headers[col]] = cell.Text
+			}
+		}
+		res[i] = m
+	}
+	return res
+}
+
+// layoutTableCells places the cells of each row into a matrix, honoring rowspan and colspan.
+func layoutTableCells(rows []*html.Node) [][]*GridCell {
+	matrix := make([][]*GridCell, len(rows))
+	for rowIndex, row := range rows {
+		col := 0
+		for _, cellNode := range rowCells(row) {
+			for col < len(matrix[rowIndex]) && matrix[rowIndex][col] != nil {
+				col++
+			}
+			cell := newGridCell(cellNode, rowIndex, col)
+			if rowIndex+cell.RowSpan > len(rows) {
+				cell.RowSpan = len(rows) - rowIndex
+			}
+			for r := rowIndex; r < rowIndex+cell.RowSpan; r++ {
+				for c := col; c < col+cell.ColSpan; c++ {
+					for len(matrix[r]) <= c {
+						matrix[r] = append(matrix[r], nil)
+					}
+					matrix[r][c] = cell
+				}
+			}
+			col += cell.ColSpan
+		}
+	}
+
+	// Rows with gaps (e.g. a short row below a rowspan) get empty cells.
+	for rowIndex, row := range matrix {
+		for col, cell := range row {
+			if cell == nil {
+				row[col] = &GridCell{Row: rowIndex, Column: col, RowSpan: 1, ColSpan: 1,
+					Attributes: map[string]string{}}
+			}
+		}
+	}
+	return matrix
+}
+
+func newGridCell(node *html.Node, row, col int) *GridCell {
+	cell := &GridCell{
+		Text:       strings.TrimSpace(nodeInnerText(node)),
+		Lines:      nodeLines(node),
+		Attributes: map[string]string{},
+		Row:        row,
+		Column:     col,
+		RowSpan:    parseSpanAttribute(node, "rowspan"),
+		ColSpan:    parseSpanAttribute(node, "colspan"),
+		Node:       node,
+	}
+	for _, attr := range node.Attr {
+		cell.Attributes[strings.ToLower(attr.Key)] = attr.Val
+	}
+	for _, link := range scrape.FindAll(node, scrape.ByTag(atom.A)) {
+		cell.Links = append(cell.Links, GridLink{
+			Text: strings.TrimSpace(nodeInnerText(link)),
+			Href: getNodeAttribute(link, "href"),
+			ID:   getNodeAttribute(link, "id"),
+		})
+	}
+	return cell
+}
+
+func allHeaderCells(cells []*html.Node) bool {
+	for _, cell := range cells {
+		if cell.DataAtom != atom.Th {
+			return false
+		}
+	}
+	return true
+}
+
+// isHiddenCell returns true if a cell is hidden from view by its attributes.
+func isHiddenCell(node *html.Node) bool {
+	if node == nil {
+		return false
+	}
+	if hasNodeAttribute(node, "hidden") {
+		return true
+	}
+	style := strings.ToLower(strings.Replace(getNodeAttribute(node, "style"), " ", "", -1))
+	return strings.Contains(style, "display:none")
+}
@@ -0,0 +1,63 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html/atom"
+)
+
+const testGridTable = `<html><body><table id="outer">
+<tr><th colspan="2">Class</th><th style="display: none">Hidden</th><th rowspan="2">Room</th></tr>
+<tr><th>Nbr</th><th>Section</th><th style="display:none">Hidden</th></tr>
+<tr>
+<td rowspan="2">1234</td><td>001</td><td style="display:none">x</td>
+<td><table><tr><td>nested</td></tr></table><a id="ROOM$0" href="#">Olin 155</a></td>
+</tr>
+<tr><td>201</td><td style="display:none">y</td><td>Phillips 101</td></tr>
+</table></body></html>`
+
+func TestParseGrid(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testGridTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, _ := scrape.Find(root, scrape.ByTag(atom.Table))
+	grid, err := ParseGrid(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeaders := []string{"Nbr", "Section", "Room"}
+	if len(grid.Headers) != len(expectedHeaders) {
+		t.Fatal("unexpected headers:", grid.Headers)
+	}
+	for i, h := range expectedHeaders {
+		if grid.Headers[i] != h {
+			t.Error("header", i, "should be", h, "but got", grid.Headers[i])
+		}
+	}
+
+	if len(grid.Rows) != 2 {
+		t.Fatal("expected 2 rows but got", len(grid.Rows))
+	}
+	if grid.Rows[1][0] != grid.Rows[0][0] || grid.Rows[1][0].Row != 0 {
+		t.Error("rowspan cell should be shared between rows")
+	}
+	if grid.Cell(1, "Section").Text != "201" || grid.Cell(1, "Room").Text != "Phillips 101" {
+		t.Error("unexpected second row:", grid.Maps()[1])
+	}
+	roomCell := grid.Cell(0, "Room")
+	if len(roomCell.Links) != 1 || roomCell.Links[0].ID != "ROOM$0" {
+		t.Error("unexpected links:", roomCell.Links)
+	}
+
+	maps, err := tableEntriesAsMaps(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maps[1]["Nbr"] != "1234" || maps[1]["Section"] != "201" {
+		t.Error("unexpected maps:", maps)
+	}
+}
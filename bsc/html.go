@@ -3,9 +3,11 @@ package bsc
 import (
 	"bytes"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/yhat/scrape"
@@ -23,6 +25,27 @@ func getNodeAttribute(node *html.Node, attribute string) string {
 	return ""
 }
 
+// hasNodeAttribute returns true if the node has the given attribute, even if its value is empty.
+// This is useful for boolean attributes like "checked" and "selected".
+func hasNodeAttribute(node *html.Node, attribute string) bool {
+	lowerAttribute := strings.ToLower(attribute)
+	for _, attr := range node.Attr {
+		if strings.ToLower(attr.Key) == lowerAttribute {
+			return true
+		}
+	}
+	return false
+}
+
+// optionValue returns the value an <option> would submit, which is its inner text if it has no
+// "value" attribute.
+func optionValue(option *html.Node) string {
+	if hasNodeAttribute(option, "value") {
+		return getNodeAttribute(option, "value")
+	}
+	return strings.TrimSpace(nodeInnerText(option))
+}
+
 func nodeInnerText(node *html.Node) string {
 	if node.Type == html.TextNode {
 		return node.Data
@@ -34,6 +57,110 @@ func nodeInnerText(node *html.Node) string {
 	return res.String()
 }
 
+// nodeLines returns the lines of text in a node, treating <br> elements as line breaks. Each line
+// is trimmed, and empty lines are omitted.
+func nodeLines(node *html.Node) []string {
+	var buf bytes.Buffer
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+		} else if n.DataAtom == atom.Br {
+			buf.WriteString("\n")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+
+	var res []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			res = append(res, trimmed)
+		}
+	}
+	return res
+}
+
+// tableRows returns the rows of a table, without including the rows of tables nested inside it.
+func tableRows(table *html.Node) []*html.Node {
+	var res []*html.Node
+	for child := table.FirstChild; child != nil; child = child.NextSibling {
+		switch child.DataAtom {
+		case atom.Tr:
+			res = append(res, child)
+		case atom.Thead, atom.Tbody, atom.Tfoot:
+			for row := child.FirstChild; row != nil; row = row.NextSibling {
+				if row.DataAtom == atom.Tr {
+					res = append(res, row)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// rowCells returns the <th> and <td> elements which are direct children of a row.
+func rowCells(row *html.Node) []*html.Node {
+	var res []*html.Node
+	for child := row.FirstChild; child != nil; child = child.NextSibling {
+		if child.DataAtom == atom.Td || child.DataAtom == atom.Th {
+			res = append(res, child)
+		}
+	}
+	return res
+}
+
+// parseSpanAttribute reads a "rowspan" or "colspan" attribute, returning 1 if it is missing or
+// invalid.
+func parseSpanAttribute(cell *html.Node, attribute string) int {
+	span, err := strconv.Atoi(strings.TrimSpace(getNodeAttribute(cell, attribute)))
+	if err != nil || span < 1 {
+		return 1
+	}
+	return span
+}
+
+// findTableWithHeader finds the first table whose first row has a heading with the given text.
+func findTableWithHeader(root *html.Node, header string) (*html.Node, bool) {
+	return scrape.Find(root, func(node *html.Node) bool {
+		if node.DataAtom != atom.Table {
+			return false
+		}
+		rows := tableRows(node)
+		if len(rows) == 0 {
+			return false
+		}
+		for _, cell := range rowCells(rows[0]) {
+			if strings.TrimSpace(nodeInnerText(cell)) == header {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// byIDPrefix matches nodes whose id begins with the given prefix.
+// PeopleSoft element IDs often have numeric suffixes that vary between pages and universities.
+func byIDPrefix(prefix string) scrape.Matcher {
+	return func(node *html.Node) bool {
+		return strings.HasPrefix(getNodeAttribute(node, "id"), prefix)
+	}
+}
+
+// parseHTMLDocument parses an HTML page and returns its root element.
+func parseHTMLDocument(body io.Reader) (*html.Node, error) {
+	nodes, err := html.ParseFragment(body, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) != 1 {
+		return nil, errors.New("invalid number of root elements")
+	}
+	return nodes[0], nil
+}
+
 // nodesWithAlignAttribute filters a list of nodes and returns only those with a non-empty "align"
 // attribute.
 func nodesWithAlignAttribute(nodes []*html.Node) []*html.Node {
@@ -51,28 +178,14 @@ func nodesWithAlignAttribute(nodes []*html.Node) []*html.Node {
 // uses the headers as map keys. It returns an array of map objects representing the rows of the
 // table, with the <th>'s as keys and their corresponding <td>'s as values.
 func tableEntriesAsMaps(table *html.Node) ([]map[string]string, error) {
-	headings := scrape.FindAll(table, scrape.ByTag(atom.Th))
-	cells := scrape.FindAll(table, scrape.ByTag(atom.Td))
-	if len(cells)%len(headings) != 0 {
-		return nil, errors.New("number of cells should be divisible by number of headings")
-	}
-
-	headingText := make([]string, len(headings))
-	for i, heading := range headings {
-		headingText[i] = strings.TrimSpace(nodeInnerText(heading))
+	grid, err := ParseGrid(table)
+	if err != nil {
+		return nil, err
 	}
-
-	maps := make([]map[string]string, len(cells)/len(headings))
-	for rowIndex := 0; rowIndex < len(maps); rowIndex++ {
-		row := map[string]string{}
-		maps[rowIndex] = row
-		for colIndex := 0; colIndex < len(headings); colIndex++ {
-			cellIndex := rowIndex*len(headings) + colIndex
-			row[headingText[colIndex]] = strings.TrimSpace(nodeInnerText(cells[cellIndex]))
-		}
+	if len(grid.Headers) == 0 {
+		return nil, errors.New("table has no headings")
 	}
-
-	return maps, nil
+	return grid.Maps(), nil
 }
 
 type loginFormInfo struct {
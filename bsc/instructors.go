@@ -0,0 +1,175 @@
+package bsc
+
+import (
+	"strings"
+)
+
+// An Instructor is a person who teaches a component.
+type Instructor struct {
+	// Name is the instructor's name as it is displayed by the Student Center.
+	Name string
+
+	// FirstName and LastName are empty if they cannot be derived from Name.
+	FirstName string
+	LastName  string
+
+	// Email is empty if the Student Center does not link to the instructor's email address.
+	Email string
+
+	Role InstructorRole
+}
+
+// An InstructorRole indicates whether an instructor is the primary instructor of a component.
+type InstructorRole int
+
+const (
+	InstructorRolePrimary InstructorRole = iota
+	InstructorRoleSecondary
+)
+
+// String returns a human-readable version of the InstructorRole.
+func (r InstructorRole) String() string {
+	if r == InstructorRolePrimary {
+		return "Primary"
+	} else {
+		return "Secondary"
+	}
+}
+
+// placeholderInstructors are the names the Student Center shows when no instructor has been
+// assigned. They are compared in lower case.
+var placeholderInstructors = map[string]bool{
+	"staff":           true,
+	"tba":             true,
+	"to be announced": true,
+	"staff tba":       true,
+}
+
+// ParseInstructors parses the names in an instructor column, which may contain several instructors
+// separated by commas or line breaks. The first instructor is treated as the primary instructor.
+//
+// Placeholders like "Staff" and "To be Announced" result in an empty list.
+func ParseInstructors(text string) []Instructor {
+	var names []string
+	for _, line := range strings.Split(text, "\n") {
+		names = append(names, splitInstructorNames(line)...)
+	}
+
+	res := make([]Instructor, 0, len(names))
+	for _, name := range names {
+		if placeholderInstructors[strings.ToLower(name)] {
+			continue
+		}
+		instructor := Instructor{Name: name, Role: InstructorRoleSecondary}
+		if len(res) == 0 {
+			instructor.Role = InstructorRolePrimary
+		}
+		instructor.FirstName, instructor.LastName = splitPersonName(name)
+		res = append(res, instructor)
+	}
+	return res
+}
+
+// parseInstructorCell parses the instructors in a table cell, using the cell's line breaks to
+// separate instructors and its mailto: links to find email addresses.
+func parseInstructorCell(cell *GridCell) []Instructor {
+	if cell == nil {
+		return []Instructor{}
+	}
+	res := ParseInstructors(strings.Join(cell.Lines, "\n"))
+	for _, link := range cell.Links {
+		if !strings.HasPrefix(strings.ToLower(link.Href), "mailto:") {
+			continue
+		}
+		email := link.Href[len("mailto:"):]
+		if i := strings.Index(email, "?"); i >= 0 {
+			email = email[:i]
+		}
+		linkText := strings.TrimRight(link.Text, ", ")
+		for i := range res {
+			if res[i].Name == linkText && res[i].Email == "" {
+				res[i].Email = email
+				break
+			}
+		}
+	}
+	return res
+}
+
+// splitInstructorNames splits a line of instructor names at commas.
+//
+// Since some universities display names as "Last, First", a comma only separates instructors if
+// every part contains multiple words. For example, "Jane Doe, John Smith" is two instructors, but
+// "Doe, Jane" is one.
+func splitInstructorNames(line string) []string {
+	var parts []string
+	for _, part := range strings.Split(line, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+	if len(parts) <= 1 {
+		return parts
+	}
+	for _, part := range parts {
+		if len(strings.Fields(part)) < 2 {
+			return []string{strings.Join(parts, ", ")}
+		}
+	}
+	return parts
+}
+
+// splitPersonName derives a first and last name from a name like "Jane Doe" or "Doe, Jane".
+// If the name is a single word, it is used as the last name.
+func splitPersonName(name string) (first, last string) {
+	if commaIndex := strings.Index(name, ","); commaIndex >= 0 {
+		return strings.TrimSpace(name[commaIndex+1:]), strings.TrimSpace(name[:commaIndex])
+	}
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return
+	} else if len(fields) == 1 {
+		return "", fields[0]
+	}
+	return fields[0], fields[len(fields)-1]
+}
+
+// UniqueInstructors returns the instructors of every component in a list of courses, without
+// duplicates. Instructors are considered the same if they have the same email address or, when an
+// email address is missing, the same name.
+//
+// An instructor is treated as primary if they are the primary instructor of any component.
+func UniqueInstructors(courses []Course) []Instructor {
+	var res []Instructor
+	for _, course := range courses {
+		for _, component := range course.Components {
+			for _, instructor := range component.Instructors {
+				index := -1
+				for i, existing := range res {
+					if sameInstructor(existing, instructor) {
+						index = i
+						break
+					}
+				}
+				if index < 0 {
+					res = append(res, instructor)
+					continue
+				}
+				if res[index].Email == "" {
+					res[index].Email = instructor.Email
+				}
+				if instructor.Role == InstructorRolePrimary {
+					res[index].Role = InstructorRolePrimary
+				}
+			}
+		}
+	}
+	return res
+}
+
+func sameInstructor(i1, i2 Instructor) bool {
+	if i1.Email != "" && i2.Email != "" {
+		return strings.EqualFold(i1.Email, i2.Email)
+	}
+	return strings.EqualFold(i1.Name, i2.Name)
+}
@@ -0,0 +1,50 @@
+package bsc
+
+import "testing"
+
+func TestParseInstructors(t *testing.T) {
+	cases := map[string][]string{
+		"Jane Doe":               {"Jane Doe"},
+		"Jane Doe, John Smith":   {"Jane Doe", "John Smith"},
+		"Doe, Jane":              {"Doe, Jane"},
+		"Doe, Jane\nSmith, John": {"Doe, Jane", "Smith, John"},
+		"Staff":                  {},
+		"To be Announced":        {},
+	}
+	for text, names := range cases {
+		instructors := ParseInstructors(text)
+		if len(instructors) != len(names) {
+			t.Error("unexpected instructors for", text, ":", instructors)
+			continue
+		}
+		for i, name := range names {
+			if instructors[i].Name != name {
+				t.Error("instructor", i, "for", text, "should be", name)
+			}
+		}
+	}
+
+	doe := ParseInstructors("Doe, Jane")[0]
+	if doe.FirstName != "Jane" || doe.LastName != "Doe" || doe.Role != InstructorRolePrimary {
+		t.Error("unexpected instructor:", doe)
+	}
+}
+
+func TestUniqueInstructors(t *testing.T) {
+	courses := []Course{
+		{Components: []Component{
+			{Instructors: []Instructor{{Name: "Jane Doe", Role: InstructorRoleSecondary}}},
+			{Instructors: []Instructor{{Name: "Jane Doe", Email: "jd1@cornell.edu"}}},
+		}},
+		{Components: []Component{
+			{Instructors: []Instructor{{Name: "John Smith"}}},
+		}},
+	}
+	unique := UniqueInstructors(courses)
+	if len(unique) != 2 {
+		t.Fatal("unexpected instructors:", unique)
+	}
+	if unique[0].Email != "jd1@cornell.edu" || unique[0].Role != InstructorRolePrimary {
+		t.Error("duplicate instructors were not merged:", unique[0])
+	}
+}
@@ -0,0 +1,241 @@
+package bsc
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A Location is a place where a component meets, parsed from the room text shown by the Student
+// Center (e.g. "Olin Hall 155" or "TBA").
+type Location struct {
+	// Raw is the unparsed room text.
+	Raw string
+
+	Campus       string
+	BuildingCode string
+	BuildingName string
+	Room         string
+
+	// Online is true for components which meet online or remotely.
+	Online bool
+
+	// TBA is true if the location has not been announced.
+	TBA bool
+}
+
+var tbaLocations = map[string]bool{
+	"tba":             true,
+	"to be announced": true,
+	"staff tba":       true,
+}
+
+var onlineLocations = map[string]bool{
+	"online":            true,
+	"online meeting":    true,
+	"remote":            true,
+	"distance learning": true,
+	"web":               true,
+	"virtual":           true,
+}
+
+// ParseLocation parses room text in the most common formats. It is used for engines which are not
+// LocationEngines.
+//
+// If the last word contains a digit, it is treated as a room number. The remaining words are
+// treated as a building code if they form a single upper-case word (e.g. "CBLS"), and as a
+// building name otherwise.
+func ParseLocation(raw string) Location {
+	loc := Location{Raw: raw}
+	text := strings.TrimSpace(raw)
+	lower := strings.ToLower(text)
+	if text == "" || tbaLocations[lower] {
+		loc.TBA = true
+		return loc
+	} else if onlineLocations[lower] {
+		loc.Online = true
+		return loc
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) > 1 && strings.IndexFunc(fields[len(fields)-1], unicode.IsDigit) >= 0 {
+		loc.Room = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+	building := strings.Join(fields, " ")
+	if len(fields) == 1 && strings.ToUpper(building) == building {
+		loc.BuildingCode = building
+	} else {
+		loc.BuildingName = building
+	}
+	return loc
+}
+
+// String returns the unparsed room text.
+func (l Location) String() string {
+	return l.Raw
+}
+
+// fillLocations sets the Location of every component from its Room, using the engine's parsing
+// rules.
+func fillLocations(uni UniversityEngine, courses []Course) {
+	for i := range courses {
+		for j := range courses[i].Components {
+			component := &courses[i].Components[j]
+			component.Location = EngineParseLocation(uni, component.Room)
+		}
+	}
+}
+
+// A Building is an entry in a BuildingDirectory.
+type Building struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Campus    string  `json:"campus"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// GeoURI returns a "geo:" URI for the building's coordinates, which most map applications can
+// open.
+func (b *Building) GeoURI() string {
+	return "geo:" + strconv.FormatFloat(b.Latitude, 'f', -1, 64) + "," +
+		strconv.FormatFloat(b.Longitude, 'f', -1, 64)
+}
+
+// A BuildingDirectory maps building codes and names to buildings.
+type BuildingDirectory struct {
+	Buildings []Building
+}
+
+// LoadBuildingDirectory reads a JSON array of buildings, such as:
+//
+//	[{"code": "OLH", "name": "Olin Hall", "campus": "Ithaca",
+//	  "latitude": 42.4455, "longitude": -76.4843}]
+func LoadBuildingDirectory(r io.Reader) (*BuildingDirectory, error) {
+	var buildings []Building
+	if err := json.NewDecoder(r).Decode(&buildings); err != nil {
+		return nil, err
+	}
+	return &BuildingDirectory{Buildings: buildings}, nil
+}
+
+// ReadBuildingDirectoryFile loads a building directory from a JSON file.
+func ReadBuildingDirectoryFile(path string) (*BuildingDirectory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBuildingDirectory(f)
+}
+
+// Lookup finds the building for a location by its building code or, failing that, its building
+// name. Both are compared case-insensitively.
+func (d *BuildingDirectory) Lookup(loc Location) (*Building, bool) {
+	for _, byCode := range []bool{true, false} {
+		for i := range d.Buildings {
+			b := &d.Buildings[i]
+			if loc.Campus != "" && b.Campus != "" && !strings.EqualFold(loc.Campus, b.Campus) {
+				continue
+			}
+			if byCode && loc.BuildingCode != "" && strings.EqualFold(b.Code, loc.BuildingCode) {
+				return b, true
+			} else if !byCode && loc.BuildingName != "" &&
+				strings.EqualFold(b.Name, loc.BuildingName) {
+				return b, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Distance returns the straight-line distance in meters between two locations. It returns false
+// if either location is not in the directory.
+func (d *BuildingDirectory) Distance(from, to Location) (float64, bool) {
+	b1, ok := d.Lookup(from)
+	if !ok {
+		return 0, false
+	}
+	b2, ok := d.Lookup(to)
+	if !ok {
+		return 0, false
+	}
+	return haversineDistance(b1.Latitude, b1.Longitude, b2.Latitude, b2.Longitude), true
+}
+
+// haversineDistance computes the great-circle distance in meters between two coordinates.
+func haversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000
+	toRadians := func(deg float64) float64 {
+		return deg * math.Pi / 180
+	}
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadius * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// A Transition is a pair of components which meet one after another on the same day.
+type Transition struct {
+	Day  time.Weekday
+	From *Component
+	To   *Component
+
+	// Gap is the number of minutes between the end of From and the start of To.
+	Gap int
+}
+
+// BackToBackComponents finds the components in a schedule which meet within maxGap minutes of each
+// other on the same day. This is useful along with BuildingDirectory.Distance to find classes
+// which are far apart.
+//
+// Components are only compared if their date ranges overlap.
+func BackToBackComponents(courses []Course, maxGap int) []Transition {
+	var components []*Component
+	for i := range courses {
+		for j := range courses[i].Components {
+			components = append(components, &courses[i].Components[j])
+		}
+	}
+
+	var res []Transition
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		var dayComponents []*Component
+		for _, component := range components {
+			for _, d := range component.WeeklyTimes.Days {
+				if d == day {
+					dayComponents = append(dayComponents, component)
+					break
+				}
+			}
+		}
+		sort.SliceStable(dayComponents, func(i, j int) bool {
+			return dayComponents[i].WeeklyTimes.Start < dayComponents[j].WeeklyTimes.Start
+		})
+		for i, from := range dayComponents {
+			for _, to := range dayComponents[i+1:] {
+				gap := int(to.WeeklyTimes.Start - from.WeeklyTimes.End)
+				if gap < 0 || gap > maxGap || !dateRangesOverlap(from, to) {
+					continue
+				}
+				res = append(res, Transition{Day: day, From: from, To: to, Gap: gap})
+			}
+		}
+	}
+	return res
+}
+
+func dateRangesOverlap(c1, c2 *Component) bool {
+	if (c1.StartDate == Date{}) || (c2.StartDate == Date{}) {
+		return true
+	}
+	return !c1.EndDate.Before(c2.StartDate) && !c2.EndDate.Before(c1.StartDate)
+}
@@ -0,0 +1,84 @@
+package bsc
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLocation(t *testing.T) {
+	cases := map[string]Location{
+		"Olin Hall 155": {Raw: "Olin Hall 155", BuildingName: "Olin Hall", Room: "155"},
+		"CBLS 100":      {Raw: "CBLS 100", BuildingCode: "CBLS", Room: "100"},
+		"TBA":           {Raw: "TBA", TBA: true},
+		"":              {TBA: true},
+		"Online":        {Raw: "Online", Online: true},
+		"Barton Hall":   {Raw: "Barton Hall", BuildingName: "Barton Hall"},
+	}
+	for raw, expected := range cases {
+		if loc := ParseLocation(raw); loc != expected {
+			t.Errorf("ParseLocation(%q) should be %+v but got %+v", raw, expected, loc)
+		}
+	}
+
+	loc := URIEngine{}.ParseLocation("Chafee A 271")
+	if loc.BuildingCode != "CHAFEE" || loc.Room != "A 271" {
+		t.Errorf("unexpected URI location: %+v", loc)
+	}
+}
+
+func TestEngineParseLocation(t *testing.T) {
+	if loc := EngineParseLocation(testMinimalEngine{}, "Olin Hall 155"); loc.Campus != "" ||
+		loc.BuildingName != "Olin Hall" {
+		t.Error("unexpected default location:", loc)
+	}
+	if loc := EngineParseLocation(CornellEngine{}, "Olin Hall 155"); loc.Campus != "Ithaca" {
+		t.Error("engine's ParseLocation was not used:", loc)
+	}
+}
+
+func TestBuildingDirectory(t *testing.T) {
+	directory, err := LoadBuildingDirectory(strings.NewReader(`[
+		{"code": "OLH", "name": "Olin Hall", "campus": "Ithaca",
+		 "latitude": 42.4455, "longitude": -76.4843},
+		{"code": "GSH", "name": "Gates Hall", "campus": "Ithaca",
+		 "latitude": 42.4450, "longitude": -76.4810}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	olin := CornellEngine{}.ParseLocation("Olin Hall 155")
+	gates := Location{BuildingCode: "gsh"}
+	if b, ok := directory.Lookup(olin); !ok || b.Code != "OLH" {
+		t.Error("could not look up Olin Hall:", b)
+	}
+	distance, ok := directory.Distance(olin, gates)
+	if !ok || math.Abs(distance-276) > 5 {
+		t.Error("unexpected distance:", distance, ok)
+	}
+	if _, ok := directory.Distance(olin, ParseLocation("TBA")); ok {
+		t.Error("distance to an unknown location should fail")
+	}
+}
+
+func TestBackToBackComponents(t *testing.T) {
+	mwf := []time.Weekday{time.Monday, time.Wednesday, time.Friday}
+	courses := []Course{
+		{Components: []Component{{WeeklyTimes: WeeklyTimes{Days: mwf, Start: 10 * 60,
+			End: 10*60 + 50}}}},
+		{Components: []Component{{WeeklyTimes: WeeklyTimes{Days: mwf[:1], Start: 11 * 60,
+			End: 12 * 60}}}},
+		{Components: []Component{{WeeklyTimes: WeeklyTimes{Days: mwf[:1], Start: 14 * 60,
+			End: 15 * 60}}}},
+	}
+	transitions := BackToBackComponents(courses, 15)
+	if len(transitions) != 1 {
+		t.Fatal("expected 1 transition but got", len(transitions))
+	}
+	if transitions[0].Day != time.Monday || transitions[0].Gap != 10 ||
+		transitions[0].To != &courses[1].Components[0] {
+		t.Errorf("unexpected transition: %+v", transitions[0])
+	}
+}
@@ -0,0 +1,279 @@
+package bsc
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// psForm stores the action and field values of the main <form> on a PeopleSoft page.
+//
+// PeopleSoft pages are driven by POSTing this form back to the server with an "ICAction" naming the
+// button or link that was clicked. The hidden fields (notably ICSID and ICStateNum) tie each POST
+// to the server-side state of the page.
+type psForm struct {
+	action string
+	values url.Values
+
+	// names lists every field in the form, including unchecked checkboxes and radio buttons which
+	// do not appear in values.
+	names []string
+
+	// options maps the name of each <select> to its options.
+	options map[string][]selectOption
+}
+
+// A selectOption is an <option> in a <select> field.
+type selectOption struct {
+	value string
+	text  string
+}
+
+// parsePSForm finds the main form on a PeopleSoft page and reads the values that a browser would
+// submit for it.
+func parsePSForm(root *html.Node) (*psForm, error) {
+	formNode, ok := scrape.Find(root, scrape.ByClass("PSForm"))
+	if !ok {
+		formNode, ok = scrape.Find(root, scrape.ByTag(atom.Form))
+		if !ok {
+			return nil, errors.New("could not find PSForm")
+		}
+	}
+
+	form := &psForm{
+		action:  getNodeAttribute(formNode, "action"),
+		values:  url.Values{},
+		options: map[string][]selectOption{},
+	}
+
+	for _, input := range scrape.FindAll(formNode, scrape.ByTag(atom.Input)) {
+		name := getNodeAttribute(input, "name")
+		if name == "" {
+			continue
+		}
+		switch strings.ToLower(getNodeAttribute(input, "type")) {
+		case "submit", "button", "image", "reset", "file":
+			continue
+		case "checkbox", "radio":
+			form.names = append(form.names, name)
+			if hasNodeAttribute(input, "checked") {
+				value := getNodeAttribute(input, "value")
+				if value == "" {
+					value = "on"
+				}
+				form.values.Add(name, value)
+			}
+		default:
+			form.names = append(form.names, name)
+			form.values.Add(name, getNodeAttribute(input, "value"))
+		}
+	}
+
+	for _, sel := range scrape.FindAll(formNode, scrape.ByTag(atom.Select)) {
+		name := getNodeAttribute(sel, "name")
+		if name == "" {
+			continue
+		}
+		form.names = append(form.names, name)
+		options := scrape.FindAll(sel, scrape.ByTag(atom.Option))
+		if len(options) == 0 {
+			continue
+		}
+		selected := options[0]
+		for _, option := range options {
+			form.options[name] = append(form.options[name], selectOption{
+				value: optionValue(option),
+				text:  strings.TrimSpace(nodeInnerText(option)),
+			})
+			if hasNodeAttribute(option, "selected") && selected == options[0] {
+				selected = option
+			}
+		}
+		form.values.Set(name, optionValue(selected))
+	}
+
+	for _, textArea := range scrape.FindAll(formNode, scrape.ByTag(atom.Textarea)) {
+		if name := getNodeAttribute(textArea, "name"); name != "" {
+			form.names = append(form.names, name)
+			form.values.Add(name, nodeInnerText(textArea))
+		}
+	}
+
+	if form.values.Get("ICSID") == "" && form.values.Get("ICStateNum") == "" {
+		return nil, errors.New("form is missing PeopleSoft state fields")
+	}
+
+	return form, nil
+}
+
+// submitValues generates the POST values for triggering the given ICAction on the form.
+// The returned values are a copy, so they may be modified without affecting the form.
+func (f *psForm) submitValues(icAction string) url.Values {
+	res := url.Values{}
+	for key, vals := range f.values {
+		res[key] = append([]string{}, vals...)
+	}
+	res.Set("ICAction", icAction)
+	res.Set("ICAJAX", "0")
+	return res
+}
+
+// fieldName returns the name of the first field in the form whose name begins with prefix.
+// PeopleSoft appends numeric suffixes (e.g. "$35$") to many field names, and these suffixes vary
+// between universities, so fields are usually best looked up by prefix.
+func (f *psForm) fieldName(prefix string) (string, bool) {
+	for _, name := range f.names {
+		if strings.HasPrefix(name, prefix) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// optionValue finds the value of the option in a <select> whose value or text is str.
+func (f *psForm) optionValue(selectName, str string) (string, bool) {
+	for _, option := range f.options[selectName] {
+		if option.value == str || option.text == str {
+			return option.value, true
+		}
+	}
+	return "", false
+}
+
+// setField sets a text field, found by prefix, in a set of values.
+func (f *psForm) setField(values url.Values, prefix, value string) error {
+	name, ok := f.fieldName(prefix)
+	if !ok {
+		return errors.New("could not find field: " + prefix)
+	}
+	values.Set(name, value)
+	return nil
+}
+
+// setSelect sets a <select> field, found by prefix, to the option whose value or text is str.
+func (f *psForm) setSelect(values url.Values, prefix, str string) error {
+	name, ok := f.fieldName(prefix)
+	if !ok {
+		return errors.New("could not find field: " + prefix)
+	}
+	value, ok := f.optionValue(name, str)
+	if !ok {
+		return errors.New("invalid option for " + strings.TrimSuffix(prefix, "$") + ": " + str)
+	}
+	values.Set(name, value)
+	return nil
+}
+
+// setCheckbox checks or unchecks a PeopleSoft checkbox in a set of values. PeopleSoft checkboxes
+// are paired with a hidden field (named like "FIELD$chk$3" for the checkbox "FIELD$3") which
+// holds "Y" or "N". The prefix should match both names, e.g. "FIELD$".
+//
+// It returns false if no such checkbox was found.
+func (f *psForm) setCheckbox(values url.Values, prefix string, checked bool) bool {
+	found := false
+	for _, name := range f.names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		found = true
+		if strings.Contains(name, "$chk") {
+			if checked {
+				values.Set(name, "Y")
+			} else {
+				values.Set(name, "N")
+			}
+		} else if checked {
+			values.Set(name, "Y")
+		} else {
+			values.Del(name)
+		}
+	}
+	return found
+}
+
+// stateNum returns the form's ICStateNum.
+func (f *psForm) stateNum() (int, error) {
+	return strconv.Atoi(f.values.Get("ICStateNum"))
+}
+
+// findAction finds a button or link on a page whose id begins with prefix and returns its id, which
+// PeopleSoft uses as the ICAction for clicking it.
+func findAction(root *html.Node, prefix string) (string, bool) {
+	node, ok := scrape.Find(root, func(node *html.Node) bool {
+		return (node.DataAtom == atom.A || node.DataAtom == atom.Input) &&
+			strings.HasPrefix(getNodeAttribute(node, "id"), prefix)
+	})
+	if !ok {
+		return "", false
+	}
+	return getNodeAttribute(node, "id"), true
+}
+
+// pageErrorMessage returns the error message which PeopleSoft displays at the top of a page, or ""
+// if there is none.
+func pageErrorMessage(root *html.Node) string {
+	for _, prefix := range []string{"DERIVED_CLSMSG_ERROR_TEXT", "DERIVED_SASSMSG_ERROR_TEXT",
+		"DERIVED_SSE_DSP_SSR_MSG_TEXT"} {
+		if node, ok := scrape.Find(root, byIDPrefix(prefix)); ok {
+			if msg := strings.TrimSpace(nodeInnerText(node)); msg != "" {
+				return msg
+			}
+		}
+	}
+	return ""
+}
+
+// fetchPage requests a page relative to the PeopleSoft root and parses it.
+func (c *Client) fetchPage(page string) (*html.Node, error) {
+	resp, err := c.RequestPage(page)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return parseHTMLDocument(resp.Body)
+}
+
+// submitForm triggers an ICAction on a PeopleSoft form and parses the resulting page.
+//
+// The values argument should generally be created with form.submitValues and then modified to
+// fill in any fields the action needs.
+//
+// Since PeopleSoft ties form state to the session, this does not attempt to re-authenticate if the
+// session has expired. Instead, it will return an error.
+func (c *Client) submitForm(form *psForm, values url.Values) (*html.Node, error) {
+	actionURL, err := c.resolveFormAction(form.action)
+	if err != nil {
+		return nil, err
+	}
+
+	c.limiter.wait()
+	c.authLock.RLock()
+	resp, err := c.client.PostForm(actionURL, values)
+	c.authLock.RUnlock()
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseHTMLDocument(resp.Body)
+}
+
+// resolveFormAction turns a form's action into an absolute URL, using the university's root URL
+// as the base for relative actions.
+func (c *Client) resolveFormAction(action string) (string, error) {
+	base, err := url.Parse(c.rootURL() + "/")
+	if err != nil {
+		return "", err
+	}
+	actionURL, err := url.Parse(action)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(actionURL).String(), nil
+}
@@ -0,0 +1,239 @@
+package bsc
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var plannerPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SSPLNR.GBL?Page=SSR_SSPLNR"
+
+const (
+	// plannerComponent is the name of the PeopleSoft component which the planner's form posts to.
+	plannerComponent = "SSR_SSPLNR"
+
+	plannerAddAction    = "DERIVED_SAA_CRS_SSR_PB_ADD_TO_PLNR$"
+	plannerMoveAction   = "DERIVED_SSR_PL_SSR_PB_MOVE$"
+	plannerDeleteAction = "DERIVED_SSR_PL_SSR_PB_DELETE$"
+	plannerTermField    = "DERIVED_SSR_PL_STRM$"
+)
+
+// A Planner is the student's "My Planner", which holds courses they plan to take in future terms.
+type Planner struct {
+	Terms []PlannerTerm
+}
+
+// A PlannerTerm is a group of courses in a Planner.
+type PlannerTerm struct {
+	// Term is the term the courses are planned for. Only its Description is set.
+	Term Term
+
+	// Unassigned is true for the group of courses which are not planned for a specific term.
+	Unassigned bool
+
+	Courses []PlannedCourse
+}
+
+// A PlannedCourse is a course in a Planner.
+type PlannedCourse struct {
+	Subject string
+	Number  string
+	Title   string
+	Units   float64
+
+	// checkbox is the name of the checkbox which selects the course for moving or deleting.
+	checkbox string
+}
+
+// ID returns the course's subject and number, as used by CatalogCourse.ID.
+func (p *PlannedCourse) ID() string {
+	return p.Subject + " " + p.Number
+}
+
+// Find finds a course in the planner by its ID (e.g. "CS 2110").
+func (p *Planner) Find(courseID string) (*PlannedCourse, *PlannerTerm, bool) {
+	for i := range p.Terms {
+		term := &p.Terms[i]
+		for j := range term.Courses {
+			if term.Courses[j].ID() == strings.Join(strings.Fields(courseID), " ") {
+				return &term.Courses[j], term, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// FetchPlanner downloads the user's planner.
+func (c *Client) FetchPlanner() (*Planner, error) {
+	root, err := c.fetchPage(plannerPath)
+	if err != nil {
+		return nil, err
+	}
+	return parsePlanner(root)
+}
+
+// AddToPlanner adds a course to the user's planner from the course catalog. The courseID is in the
+// format used by CatalogCourse.ID. If term is not nil, the course is then moved to that term.
+func (c *Client) AddToPlanner(courseID string, term *Term) (*Planner, error) {
+	entryPage, _, err := c.openCatalogEntry(courseID)
+	if err != nil {
+		return nil, err
+	}
+	addAction, ok := findAction(entryPage, plannerAddAction)
+	if !ok {
+		return nil, errors.New("could not find the button to add to the planner")
+	}
+	root, err := c.clickCatalogAction(entryPage, addAction)
+	if err != nil {
+		return nil, err
+	}
+	if msg := pageErrorMessage(root); msg != "" {
+		return nil, errors.New(msg)
+	}
+	if term != nil {
+		return c.MoveInPlanner(courseID, *term)
+	}
+	return c.FetchPlanner()
+}
+
+// MoveInPlanner plans a course in the user's planner for a different term. The term may be given
+// by code or description.
+func (c *Client) MoveInPlanner(courseID string, term Term) (*Planner, error) {
+	return c.plannerAction(courseID, plannerMoveAction, func(form *psForm,
+		values url.Values) error {
+		return setTermSelect(form, values, plannerTermField, term)
+	})
+}
+
+// RemoveFromPlanner removes a course from the user's planner.
+func (c *Client) RemoveFromPlanner(courseID string) (*Planner, error) {
+	return c.plannerAction(courseID, plannerDeleteAction, nil)
+}
+
+// plannerAction selects a course in the planner, lets fill set any other fields, and triggers an
+// action on the selected course. It returns the updated planner.
+func (c *Client) plannerAction(courseID, action string, fill func(form *psForm,
+	values url.Values) error) (*Planner, error) {
+	root, err := c.fetchPage(plannerPath)
+	if err != nil {
+		return nil, err
+	}
+	planner, err := parsePlanner(root)
+	if err != nil {
+		return nil, err
+	}
+	course, _, ok := planner.Find(courseID)
+	if !ok {
+		return nil, errors.New("course is not in the planner: " + courseID)
+	} else if course.checkbox == "" {
+		return nil, errors.New("course cannot be selected: " + courseID)
+	}
+
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	actionID, ok := findAction(root, action)
+	if !ok {
+		return nil, errors.New("could not find action: " + strings.TrimSuffix(action, "$"))
+	}
+	values := form.submitValues(actionID)
+	checkRowCheckbox(values, course.checkbox)
+	if fill != nil {
+		if err := fill(form, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if root, err = c.submitForm(form, values); err != nil {
+		return nil, err
+	}
+	if msg := pageErrorMessage(root); msg != "" {
+		return nil, errors.New(msg)
+	}
+	return parsePlanner(root)
+}
+
+// parsePlanner parses the planner page. Like the schedule, each term is a group box whose title is
+// the term and which contains a table of courses.
+func parsePlanner(root *html.Node) (*Planner, error) {
+	var planner Planner
+	for _, box := range scrape.FindAll(root, scrape.ByClass("PSGROUPBOXWBO")) {
+		titleElement, ok := scrape.Find(box, scrape.ByClass("PAGROUPDIVIDER"))
+		if !ok {
+			continue
+		}
+		table, ok := findTableWithHeader(box, "Course")
+		if !ok {
+			continue
+		}
+		title := strings.TrimSpace(nodeInnerText(titleElement))
+		term := PlannerTerm{
+			Term:       Term{Description: title},
+			Unassigned: strings.Contains(strings.ToLower(title), "unassigned"),
+		}
+		if term.Unassigned {
+			term.Term = Term{}
+		}
+
+		grid, err := ParseGrid(table)
+		if err != nil {
+			return nil, err
+		}
+		for i := range grid.Rows {
+			course, err := parsePlannedCourse(grid, i)
+			if err != nil {
+				return nil, err
+			}
+			if course != nil {
+				term.Courses = append(term.Courses, *course)
+			}
+		}
+		planner.Terms = append(planner.Terms, term)
+	}
+	if len(planner.Terms) == 0 {
+		// An empty planner has no groups, so the page is recognized by its form instead.
+		form, err := parsePSForm(root)
+		if err != nil || !strings.Contains(form.action, plannerComponent) {
+			return nil, errors.New("could not find planner")
+		}
+	}
+	return &planner, nil
+}
+
+// parsePlannedCourse parses a row of a planner table. It returns nil for rows without a course.
+func parsePlannedCourse(grid *Grid, row int) (*PlannedCourse, error) {
+	courseCell := grid.Cell(row, "Course")
+	fields := strings.Fields(courseCell.Text)
+	if len(fields) != 2 {
+		return nil, nil
+	}
+	course := &PlannedCourse{Subject: fields[0], Number: fields[1]}
+	if cell := grid.Cell(row, "Description"); cell != nil {
+		course.Title = cell.Text
+	}
+	if cell := grid.Cell(row, "Units"); cell != nil && cell.Text != "" {
+		units, err := strconv.ParseFloat(cell.Text, 64)
+		if err != nil {
+			return nil, errors.New("invalid units: " + cell.Text)
+		}
+		course.Units = units
+	}
+
+	if courseCell.Node != nil && courseCell.Node.Parent != nil {
+		checkbox, ok := scrape.Find(courseCell.Node.Parent, func(node *html.Node) bool {
+			return node.DataAtom == atom.Input &&
+				strings.ToLower(getNodeAttribute(node, "type")) == "checkbox" &&
+				!strings.Contains(getNodeAttribute(node, "name"), "$chk$")
+		})
+		if ok {
+			course.checkbox = getNodeAttribute(checkbox, "name")
+		}
+	}
+	return course, nil
+}
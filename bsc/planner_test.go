@@ -0,0 +1,102 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testPlannerPage = `<html><body>
+<form class="PSForm" action="SSR_SSPLNR.GBL">
+<input type="hidden" name="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" value="2">
+<div class="PSGROUPBOXWBO"><div class="PAGROUPDIVIDER">Unassigned Courses</div>
+<table><tr><th>Select</th><th>Course</th><th>Description</th><th>Units</th></tr>
+<tr><td><input type="checkbox" name="SSR_PLNR_SEL$0" value="Y">
+<input type="hidden" name="SSR_PLNR_SEL$chk$0" value="N"></td>
+<td>CS 3110</td><td>Data Structures and Functional Programming</td><td>4.00</td></tr>
+</table></div>
+<div class="PSGROUPBOXWBO"><div class="PAGROUPDIVIDER">Spring 2016</div>
+<table><tr><th>Select</th><th>Course</th><th>Description</th><th>Units</th></tr>
+<tr><td><input type="checkbox" name="SSR_PLNR_SEL$1" value="Y">
+<input type="hidden" name="SSR_PLNR_SEL$chk$1" value="N"></td>
+<td>MATH  2940</td><td>Linear Algebra</td><td>4.00</td></tr>
+<tr><td><input type="checkbox" name="SSR_PLNR_SEL$2" value="Y">
+<input type="hidden" name="SSR_PLNR_SEL$chk$2" value="N"></td>
+<td>PE 1100</td><td>Rock Climbing</td><td></td></tr>
+</table></div>
+<input type="button" id="DERIVED_SSR_PL_SSR_PB_DELETE$0" value="Delete">
+</form>
+</body></html>`
+
+func TestParsePlanner(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testPlannerPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	planner, err := parsePlanner(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(planner.Terms) != 2 {
+		t.Fatal("expected 2 terms but got", len(planner.Terms))
+	}
+	if !planner.Terms[0].Unassigned || planner.Terms[0].Term.Description != "" {
+		t.Error("first group should be unassigned:", planner.Terms[0])
+	}
+	spring := planner.Terms[1]
+	if spring.Unassigned || spring.Term.Description != "Spring 2016" {
+		t.Error("unexpected second group:", spring.Term)
+	}
+	if len(spring.Courses) != 2 {
+		t.Fatal("expected 2 spring courses but got", len(spring.Courses))
+	}
+	if spring.Courses[0].ID() != "MATH 2940" || spring.Courses[0].Units != 4 ||
+		spring.Courses[0].Title != "Linear Algebra" {
+		t.Error("unexpected course:", spring.Courses[0])
+	}
+	if spring.Courses[1].Units != 0 {
+		t.Error("missing units should be 0 but got", spring.Courses[1].Units)
+	}
+
+	course, term, ok := planner.Find("CS  3110")
+	if !ok || !term.Unassigned || course.checkbox != "SSR_PLNR_SEL$0" {
+		t.Error("unexpected find result:", course, term, ok)
+	}
+	if _, _, ok := planner.Find("CS 2110"); ok {
+		t.Error("found a course which is not planned")
+	}
+}
+
+func TestParsePlannerEmpty(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(`<html><body>
+<form class="PSForm" action="SSR_SSPLNR.GBL">
+<input type="hidden" name="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" value="2">
+<span>You have no courses in your planner.</span>
+</form>
+</body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	planner, err := parsePlanner(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(planner.Terms) != 0 {
+		t.Error("expected an empty planner but got", planner.Terms)
+	}
+}
+
+func TestParsePlannerMissing(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(`<html><body>
+<form class="PSForm" action="SSR_SSENRL_CART.GBL">
+<input type="hidden" name="ICSID" value="abc">
+</form>
+</body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsePlanner(root); err == nil {
+		t.Error("expected an error for a page without a planner")
+	}
+}
@@ -0,0 +1,36 @@
+package bsc
+
+import (
+	"sync"
+	"time"
+)
+
+// A rateLimiter enforces a minimum interval between requests. It may be shared between Clients
+// which use the same account, so that they are limited as a group.
+type rateLimiter struct {
+	lock     sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next request may be made.
+func (r *rateLimiter) wait() {
+	r.lock.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	sleepTime := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.lock.Unlock()
+
+	if sleepTime > 0 {
+		time.Sleep(sleepTime)
+	}
+}
+
+func (r *rateLimiter) setInterval(interval time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.interval = interval
+}
@@ -0,0 +1,423 @@
+package bsc
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var academicRequirementsPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SAA_SS_DPR_ADB.GBL"
+
+const (
+	requirementsExpandAllAction = "DERIVED_SAA_DPR_SSS_EXPAND_ALL"
+
+	// The titles of the three levels of the report are in group boxes with these id prefixes.
+	requirementGroupPrefix = "DERIVED_SAA_DPR_GROUPBOX1GP$"
+	requirementPrefix      = "DERIVED_SAA_DPR_GROUPBOX2GP$"
+	lineItemPrefix         = "DERIVED_SAA_DPR_GROUPBOX3GP$"
+)
+
+var (
+	requirementUnitsPattern   = regexp.MustCompile(`Units:\s*([^:]*)`)
+	requirementCoursesPattern = regexp.MustCompile(`Courses:\s*([^:]*)`)
+	requirementCountPattern   = regexp.MustCompile(`([\d.]+)\s+(required|taken|needed)`)
+)
+
+// A RequirementStatus indicates whether a part of an academic requirements report is satisfied.
+type RequirementStatus int
+
+const (
+	RequirementStatusSatisfied RequirementStatus = iota
+	RequirementStatusNotSatisfied
+	RequirementStatusInProgress
+	RequirementStatusOther
+)
+
+// ParseRequirementStatus takes a human-readable string (e.g. "Not Satisfied") and turns it into a
+// RequirementStatus. Unrecognized strings are treated as RequirementStatusOther.
+func ParseRequirementStatus(str string) RequirementStatus {
+	mapping := map[string]RequirementStatus{
+		"Satisfied":     RequirementStatusSatisfied,
+		"Not Satisfied": RequirementStatusNotSatisfied,
+		"In Progress":   RequirementStatusInProgress,
+	}
+	if status, ok := mapping[str]; ok {
+		return status
+	} else {
+		return RequirementStatusOther
+	}
+}
+
+// String returns a human-readable version of the RequirementStatus.
+func (r RequirementStatus) String() string {
+	names := map[RequirementStatus]string{
+		RequirementStatusSatisfied:    "Satisfied",
+		RequirementStatusNotSatisfied: "Not Satisfied",
+		RequirementStatusInProgress:   "In Progress",
+	}
+	if name, ok := names[r]; ok {
+		return name
+	} else {
+		return "Other"
+	}
+}
+
+// RequirementCounts gives the number of units or courses which a requirement needs.
+type RequirementCounts struct {
+	Required float64
+	Taken    float64
+	Needed   float64
+}
+
+// RequirementInfo is the information shown for every level of an academic requirements report.
+type RequirementInfo struct {
+	Title       string
+	Status      RequirementStatus
+	Description string
+
+	// Units and Courses are nil if the report does not count units or courses for this part.
+	Units   *RequirementCounts
+	Courses *RequirementCounts
+}
+
+// Satisfied returns true if the status is RequirementStatusSatisfied.
+func (r *RequirementInfo) Satisfied() bool {
+	return r.Status == RequirementStatusSatisfied
+}
+
+// AcademicRequirements is the user's academic requirements report (degree audit).
+type AcademicRequirements struct {
+	Groups []RequirementGroup
+}
+
+// A RequirementGroup is a top-level part of an academic requirements report, such as a university,
+// college, or major requirement.
+type RequirementGroup struct {
+	RequirementInfo
+	Requirements []Requirement
+}
+
+// A Requirement is a requirement within a RequirementGroup.
+type Requirement struct {
+	RequirementInfo
+	LineItems []LineItem
+}
+
+// A LineItem is the most specific part of an academic requirements report. It lists the courses
+// used to satisfy it and the courses which could be taken to satisfy it.
+//
+// Courses which are shown directly under a Requirement are put in an untitled LineItem with the
+// requirement's status.
+type LineItem struct {
+	RequirementInfo
+	CoursesUsed      []RequirementCourse
+	CoursesAvailable []RequirementCourse
+}
+
+// A RequirementCourse is a course listed in an academic requirements report.
+type RequirementCourse struct {
+	Department string
+
+	// Number may contain PeopleSoft wildcards for available courses, e.g. "3@" for any 3000-level
+	// course.
+	Number      string
+	Description string
+	Units       float64
+
+	// When, Grade, and Status are set for courses used towards a requirement. When is the term in
+	// which the course was taken and Status is e.g. "Taken" or "In Progress".
+	When   string
+	Grade  string
+	Status string
+
+	// TypicallyOffered is set for available courses.
+	TypicallyOffered string
+}
+
+// ID returns the course's subject and number, as used by CatalogCourse.ID.
+func (r *RequirementCourse) ID() string {
+	return r.Department + " " + r.Number
+}
+
+// Wildcard returns true if the course number contains a PeopleSoft wildcard.
+func (r *RequirementCourse) Wildcard() bool {
+	return strings.ContainsAny(r.Number, "@*")
+}
+
+// ClassQuery returns a ClassQuery for finding sections of the course with SearchClasses. If the
+// course number is a wildcard, the query only limits the subject.
+func (r *RequirementCourse) ClassQuery() ClassQuery {
+	if r.Wildcard() {
+		return ClassQuery{Subject: r.Department}
+	}
+	return ClassQuery{
+		Subject:            r.Department,
+		CatalogNumber:      r.Number,
+		CatalogNumberMatch: CatalogNumberExact,
+	}
+}
+
+// Remaining returns the line items which are not satisfied, in the order they appear in the
+// report. Line items in satisfied requirements are skipped, since some reports leave alternatives
+// unsatisfied once a requirement has been met.
+func (a *AcademicRequirements) Remaining() []*LineItem {
+	var res []*LineItem
+	for i := range a.Groups {
+		group := &a.Groups[i]
+		if group.Satisfied() {
+			continue
+		}
+		for j := range group.Requirements {
+			requirement := &group.Requirements[j]
+			if requirement.Satisfied() {
+				continue
+			}
+			for k := range requirement.LineItems {
+				if item := &requirement.LineItems[k]; !item.Satisfied() {
+					res = append(res, item)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// FetchAcademicRequirements downloads and parses the user's academic requirements report.
+func (c *Client) FetchAcademicRequirements() (*AcademicRequirements, error) {
+	root, err := c.fetchPage(academicRequirementsPath)
+	if err != nil {
+		return nil, err
+	}
+	if root, err = c.expandRequirements(root); err != nil {
+		return nil, err
+	}
+	return parseAcademicRequirements(root)
+}
+
+// expandRequirements clicks the "Expand All" button on a requirements report, if there is one,
+// so that the courses of satisfied requirements are included.
+func (c *Client) expandRequirements(root *html.Node) (*html.Node, error) {
+	action, ok := findAction(root, requirementsExpandAllAction)
+	if !ok {
+		return root, nil
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	return c.submitForm(form, form.submitValues(action))
+}
+
+// parseAcademicRequirements parses a requirements report. The report is read in document order:
+// each title starts a new group, requirement, or line item, the text which follows it describes
+// it, and course tables belong to the most recent line item.
+func parseAcademicRequirements(root *html.Node) (*AcademicRequirements, error) {
+	report := &AcademicRequirements{}
+	var info *RequirementInfo
+	var text []string
+	finishInfo := func() {
+		if info != nil {
+			parseRequirementText(info, strings.Join(text, " "))
+		}
+		info, text = nil, nil
+	}
+	currentRequirement := func() *Requirement {
+		group := &report.Groups[len(report.Groups)-1]
+		if len(group.Requirements) == 0 {
+			implicit := Requirement{}
+			implicit.Status = group.Status
+			group.Requirements = append(group.Requirements, implicit)
+		}
+		return &group.Requirements[len(group.Requirements)-1]
+	}
+	currentLineItem := func() *LineItem {
+		requirement := currentRequirement()
+		if len(requirement.LineItems) == 0 {
+			implicit := LineItem{}
+			implicit.Status = requirement.Status
+			requirement.LineItems = append(requirement.LineItems, implicit)
+		}
+		return &requirement.LineItems[len(requirement.LineItems)-1]
+	}
+
+	var parseErr error
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		id := getNodeAttribute(n, "id")
+		switch {
+		case strings.HasPrefix(id, requirementGroupPrefix):
+			finishInfo()
+			report.Groups = append(report.Groups, RequirementGroup{})
+			info = &report.Groups[len(report.Groups)-1].RequirementInfo
+			parseRequirementTitle(info, n)
+			return
+		case len(report.Groups) == 0:
+		case strings.HasPrefix(id, requirementPrefix):
+			finishInfo()
+			group := &report.Groups[len(report.Groups)-1]
+			group.Requirements = append(group.Requirements, Requirement{})
+			info = &group.Requirements[len(group.Requirements)-1].RequirementInfo
+			parseRequirementTitle(info, n)
+			return
+		case strings.HasPrefix(id, lineItemPrefix):
+			finishInfo()
+			requirement := currentRequirement()
+			requirement.LineItems = append(requirement.LineItems, LineItem{})
+			info = &requirement.LineItems[len(requirement.LineItems)-1].RequirementInfo
+			parseRequirementTitle(info, n)
+			return
+		case n.DataAtom == atom.Table && isRequirementCourseTable(n):
+			finishInfo()
+			courses, used, err := parseRequirementCourses(n)
+			if err != nil {
+				parseErr = err
+				return
+			}
+			item := currentLineItem()
+			if used {
+				item.CoursesUsed = append(item.CoursesUsed, courses...)
+			} else {
+				item.CoursesAvailable = append(item.CoursesAvailable, courses...)
+			}
+			return
+		case n.Type == html.TextNode:
+			if info != nil {
+				if str := strings.Join(strings.Fields(n.Data), " "); str != "" {
+					text = append(text, str)
+				}
+			}
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	finishInfo()
+
+	if parseErr != nil {
+		return nil, parseErr
+	} else if len(report.Groups) == 0 {
+		return nil, errors.New("could not find academic requirements")
+	}
+	return report, nil
+}
+
+// parseRequirementTitle reads a title like "Not Satisfied: Major in Computer Science". Some
+// universities show the status as an image instead of a prefix.
+func parseRequirementTitle(info *RequirementInfo, node *html.Node) {
+	title := strings.Join(strings.Fields(nodeInnerText(node)), " ")
+	info.Status = RequirementStatusOther
+	if idx := strings.Index(title, ":"); idx >= 0 {
+		if status := ParseRequirementStatus(title[:idx]); status != RequirementStatusOther {
+			info.Status = status
+			title = strings.TrimSpace(title[idx+1:])
+		}
+	}
+	if info.Status == RequirementStatusOther {
+		if img, ok := scrape.Find(node, scrape.ByTag(atom.Img)); ok {
+			info.Status = ParseRequirementStatus(getNodeAttribute(img, "alt"))
+		}
+	}
+	info.Title = title
+}
+
+// parseRequirementText reads the description and counts which follow a title, such as
+// "Complete two courses. Units: 8.00 required, 4.00 taken, 4.00 needed".
+func parseRequirementText(info *RequirementInfo, text string) {
+	description := text
+	for _, label := range []string{"Units:", "Courses:", "GPA:"} {
+		if idx := strings.Index(description, label); idx >= 0 {
+			description = description[:idx]
+		}
+	}
+	info.Description = strings.TrimSpace(description)
+	info.Units = parseRequirementCounts(requirementUnitsPattern, text)
+	info.Courses = parseRequirementCounts(requirementCoursesPattern, text)
+}
+
+func parseRequirementCounts(pattern *regexp.Regexp, text string) *RequirementCounts {
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	var counts RequirementCounts
+	found := false
+	for _, count := range requirementCountPattern.FindAllStringSubmatch(match[1], -1) {
+		num, err := strconv.ParseFloat(count[1], 64)
+		if err != nil {
+			continue
+		}
+		found = true
+		switch count[2] {
+		case "required":
+			counts.Required = num
+		case "taken":
+			counts.Taken = num
+		case "needed":
+			counts.Needed = num
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &counts
+}
+
+// isRequirementCourseTable returns true for tables whose heading row includes "Course".
+func isRequirementCourseTable(table *html.Node) bool {
+	rows := tableRows(table)
+	if len(rows) == 0 {
+		return false
+	}
+	for _, cell := range rowCells(rows[0]) {
+		if strings.TrimSpace(nodeInnerText(cell)) == "Course" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRequirementCourses parses a table of courses. Tables of courses used towards a requirement
+// have "When" and "Grade" columns; tables of available courses do not.
+func parseRequirementCourses(table *html.Node) (courses []RequirementCourse, used bool,
+	err error) {
+	grid, err := ParseGrid(table)
+	if err != nil {
+		return nil, false, err
+	}
+	used = grid.Column("When") >= 0 || grid.Column("Grade") >= 0
+	for i, row := range grid.Maps() {
+		fields := strings.Fields(row["Course"])
+		if len(fields) != 2 {
+			continue
+		}
+		course := RequirementCourse{
+			Department:       fields[0],
+			Number:           fields[1],
+			Description:      row["Description"],
+			When:             row["When"],
+			Grade:            row["Grade"],
+			Status:           row["Status"],
+			TypicallyOffered: row["Typically Offered"],
+		}
+		if cell := grid.Cell(i, "Status"); course.Status == "" && cell != nil && cell.Node != nil {
+			// The status is often shown as an icon.
+			if img, ok := scrape.Find(cell.Node, scrape.ByTag(atom.Img)); ok {
+				course.Status = getNodeAttribute(img, "alt")
+			}
+		}
+		if units := row["Units"]; units != "" {
+			if course.Units, err = strconv.ParseFloat(units, 64); err != nil {
+				return nil, false, errors.New("invalid units: " + units)
+			}
+		}
+		courses = append(courses, course)
+	}
+	return courses, used, nil
+}
@@ -0,0 +1,89 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testRequirementsPage = `<html><body>
+<div id="DERIVED_SAA_DPR_GROUPBOX1GP$0">Not Satisfied: Major in Computer Science</div>
+<span>All requirements for the major.</span>
+<span>Units: 40.00 required, 28.00 taken, 12.00 needed</span>
+<div id="DERIVED_SAA_DPR_GROUPBOX2GP$0">Satisfied: Introductory Programming</div>
+<div id="DERIVED_SAA_DPR_GROUPBOX3GP$0"><img alt="Satisfied">CS 2110</div>
+<span>Courses: 1 required, 1 taken, 0 needed</span>
+<table>
+<tr><th>Course</th><th>Description</th><th>Units</th><th>When</th><th>Grade</th><th>Status</th></tr>
+<tr><td>CS 2110</td><td>Object-Oriented Programming</td><td>3.00</td><td>FA14</td><td>A-</td>
+<td><img alt="Taken"></td></tr>
+</table>
+<div id="DERIVED_SAA_DPR_GROUPBOX2GP$1">Not Satisfied: Upper-Level Electives</div>
+<span>Complete two courses at the 4000 level.</span>
+<table>
+<tr><th>Course</th><th>Description</th><th>Units</th><th>Typically Offered</th></tr>
+<tr><td>CS 4410</td><td>Operating Systems</td><td>4.00</td><td>Fall</td></tr>
+<tr><td>CS 4@</td><td></td><td></td><td></td></tr>
+</table>
+<div id="DERIVED_SAA_DPR_GROUPBOX1GP$1">Satisfied: University Requirements</div>
+</body></html>`
+
+func TestParseAcademicRequirements(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testRequirementsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := parseAcademicRequirements(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Groups) != 2 {
+		t.Fatal("expected 2 groups but got", len(report.Groups))
+	}
+	major := report.Groups[0]
+	if major.Title != "Major in Computer Science" ||
+		major.Status != RequirementStatusNotSatisfied ||
+		major.Description != "All requirements for the major." {
+		t.Error("unexpected group:", major.RequirementInfo)
+	}
+	if major.Units == nil || *major.Units != (RequirementCounts{40, 28, 12}) {
+		t.Error("unexpected units:", major.Units)
+	}
+	if len(major.Requirements) != 2 {
+		t.Fatal("expected 2 requirements but got", len(major.Requirements))
+	}
+
+	intro := major.Requirements[0]
+	if !intro.Satisfied() || len(intro.LineItems) != 1 {
+		t.Fatal("unexpected requirement:", intro)
+	}
+	item := intro.LineItems[0]
+	if item.Title != "CS 2110" || !item.Satisfied() || item.Courses == nil ||
+		item.Courses.Taken != 1 {
+		t.Error("unexpected line item:", item.RequirementInfo)
+	}
+	if len(item.CoursesUsed) != 1 || item.CoursesUsed[0].Grade != "A-" ||
+		item.CoursesUsed[0].Status != "Taken" || item.CoursesUsed[0].Units != 3 {
+		t.Error("unexpected courses used:", item.CoursesUsed)
+	}
+
+	electives := major.Requirements[1]
+	if len(electives.LineItems) != 1 || electives.LineItems[0].Title != "" {
+		t.Fatal("expected an untitled line item:", electives.LineItems)
+	}
+	available := electives.LineItems[0].CoursesAvailable
+	if len(available) != 2 || available[0].TypicallyOffered != "Fall" {
+		t.Fatal("unexpected available courses:", available)
+	}
+	if query := available[0].ClassQuery(); query.Subject != "CS" ||
+		query.CatalogNumber != "4410" {
+		t.Error("unexpected query:", query)
+	}
+	if !available[1].Wildcard() || available[1].ClassQuery().CatalogNumber != "" {
+		t.Error("wildcard course was not recognized:", available[1])
+	}
+
+	remaining := report.Remaining()
+	if len(remaining) != 1 || remaining[0] != &report.Groups[0].Requirements[1].LineItems[0] {
+		t.Error("unexpected remaining line items:", remaining)
+	}
+}
@@ -2,74 +2,326 @@ package bsc
 
 import (
 	"errors"
-	"io"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/yhat/scrape"
 	"golang.org/x/net/html"
-	"golang.org/x/net/html/atom"
 )
 
+// ScheduleOptions specifies what information FetchScheduleForTerm should download.
+type ScheduleOptions struct {
+	// FetchMoreInfo indicates that the components of each course should have extra information.
+	// This requires two extra requests per component.
+	FetchMoreInfo bool
+
+	// ClassDetail indicates that each component's Detail should be filled in. This implies
+	// FetchMoreInfo.
+	ClassDetail bool
+
+	// Parallelism is the maximum number of PeopleSoft sessions to use when fetching extra
+	// information. Each session beyond the first requires a separate login (and a logout once the
+	// fetch is done), but sessions can load class details concurrently. Values less than 2 fetch
+	// everything sequentially, and values above maxScheduleSessions are capped. Requests from
+	// every session count towards the Client's minimum request interval.
+	//
+	// If an extra session fails to log in or loses its connection, the other sessions take over
+	// its work.
+	Parallelism int
+
+	// Lenient indicates that parse failures should be recorded in the Schedule's Warnings instead
+	// of causing the fetch to fail. Courses and components which could only be partly parsed are
+	// still included.
+	Lenient bool
+}
+
+// maxScheduleSessions limits ScheduleOptions.Parallelism, since every extra session is a separate
+// login.
+const maxScheduleSessions = 4
+
+// sectionActionPrefix is the prefix of the ids of the links from the schedule to each class's
+// detail page. The links are numbered through the whole page, including courses which could not be
+// parsed, so the ids must be read from each row.
+const sectionActionPrefix = "MTG_SECTION$"
+
+// logoutPath is the page which ends a PeopleSoft session.
+const logoutPath = "/EMPLOYEE/HRMS/?cmd=logout"
+
+// A Schedule is the list of courses in which the user is enrolled for a given term.
+type Schedule struct {
+	Term    Term
+	Courses []Course
+
+	// Warnings lists the data which could not be parsed. It is always empty in strict mode.
+	Warnings []ParseWarning
+}
+
 // fetchExtraScheduleInfo gets more information about each component.
 //
-// The rootNode argument should be the parsed schedule list view.
-func fetchExtraScheduleInfo(client *http.Client, courses []Course, rootNode *html.Node) error {
-	psForm, ok := scrape.Find(rootNode, scrape.ByClass("PSForm"))
-	if !ok {
-		return errors.New("could not find PSForm")
-	}
-	icsid, ok := scrape.Find(psForm, scrape.ById("ICSID"))
-	if !ok {
-		return errors.New("could not find ICSID")
+// The rootNode argument should be the parsed schedule list view for the given term. Pages which
+// cannot be parsed are reported to the warningCollector.
+//
+// If opts.Parallelism is greater than 1, extra sessions are opened to fetch the information in
+// parallel. The rootNode is used by the first session.
+func (c *Client) fetchExtraScheduleInfo(term Term, courses []Course, rootNode *html.Node,
+	opts ScheduleOptions, warnings *warningCollector) error {
+	type job struct {
+		course        *Course
+		component     *Component
+		sectionAction string
+		courseOpen    bool
+		parseErr      error
+		warnings      warningCollector
 	}
-
-	formAction := getNodeAttribute(psForm, "action")
-	sid := getNodeAttribute(icsid, "value")
-
 	// TODO: figure out if there's a way to make this more robust or to load it lazily.
-	sectionIndex := 0
+	var jobs []*job
 	for courseIndex := range courses {
 		course := &courses[courseIndex]
 		for componentIndex := range course.Components {
 			component := &course.Components[componentIndex]
-
-			postData := generateClassDetailForm(sid, sectionIndex)
-			res, reqErr := client.PostForm(formAction, postData)
-			if res != nil {
-				defer res.Body.Close()
-			}
-			if reqErr != nil {
-				return reqErr
+			if component.sectionAction == "" {
+				err := warnings.add(ParseWarning{
+					Course:    course.Name,
+					Component: component.Section,
+					Field:     "Section",
+					Err:       errors.New("could not find class detail link"),
+				})
+				if err != nil {
+					return err
+				}
+				continue
 			}
+			jobs = append(jobs, &job{
+				course:        course,
+				component:     component,
+				sectionAction: component.sectionAction,
+				warnings:      warningCollector{lenient: true},
+			})
+		}
+	}
 
-			courseOpen, parseErr := parseExtraComponentInfo(res.Body, component)
-			if parseErr != nil {
-				return parseErr
-			}
-			course.Open = &courseOpen
+	sessionCount := opts.Parallelism
+	if sessionCount > maxScheduleSessions {
+		sessionCount = maxScheduleSessions
+	}
+	if sessionCount > len(jobs) {
+		sessionCount = len(jobs)
+	}
+	if sessionCount < 1 {
+		sessionCount = 1
+	}
 
-			postData = generateClassDetailBackForm(sid, sectionIndex)
-			res, reqErr = client.PostForm(formAction, postData)
-			if res != nil {
-				defer res.Body.Close()
+	// Every session takes jobs from the same queue. When a session fails, its current job is put
+	// back for the remaining sessions, so the fetch only fails if every session does.
+	queue := make(chan *job, len(jobs))
+	for _, j := range jobs {
+		queue <- j
+	}
+	var lock sync.Mutex
+	remaining := len(jobs)
+	var firstErr error
+	fail := func(j *job, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if j != nil {
+			queue <- j
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < sessionCount; i++ {
+		wg.Add(1)
+		go func(sessionIndex int) {
+			defer wg.Done()
+			session, err := c.openClassDetailSession(term, rootNode, sessionIndex)
+			if err != nil {
+				fail(nil, err)
+				return
 			}
-			if reqErr != nil {
-				return reqErr
+			defer session.close()
+			for job := range queue {
+				job.courseOpen, job.parseErr, err = session.fetch(job.sectionAction, job.component,
+					opts.ClassDetail, &job.warnings)
+				if err != nil {
+					fail(job, err)
+					return
+				}
+				lock.Lock()
+				remaining--
+				if remaining == 0 {
+					close(queue)
+				}
+				lock.Unlock()
 			}
+		}(i)
+	}
+	wg.Wait()
 
-			sectionIndex++
+	if remaining > 0 {
+		return firstErr
+	}
+	for _, job := range jobs {
+		for _, warning := range job.warnings.warnings {
+			warning.Course = job.course.Name
+			if err := warnings.add(warning); err != nil {
+				return err
+			}
+		}
+		if job.parseErr != nil {
+			err := warnings.add(ParseWarning{
+				Course:    job.course.Name,
+				Component: job.component.Section,
+				Field:     "Class Availability",
+				Err:       job.parseErr,
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			courseOpen := job.courseOpen
+			job.course.Open = &courseOpen
+			job.component.Open = &courseOpen
 		}
 	}
 
 	return nil
 }
 
+// A classDetailSession fetches class detail pages from one instance of the schedule list view.
+//
+// PeopleSoft stores the state of each page instance on the server and numbers every request, so a
+// classDetailSession must only be used by one goroutine at a time.
+type classDetailSession struct {
+	client     *Client
+	clone      bool
+	unlock     func()
+	formAction string
+	sid        string
+	stateNum   int
+}
+
+// openClassDetailSession creates a classDetailSession. The first session uses the client and the
+// schedule page that has already been loaded. Other sessions use separate logins, since each
+// PeopleSoft session can only have one instance of the schedule list view at a time.
+func (c *Client) openClassDetailSession(term Term, rootNode *html.Node,
+	sessionIndex int) (*classDetailSession, error) {
+	client := c
+	if sessionIndex > 0 {
+		var err error
+		client, err = c.cloneSession()
+		if err != nil {
+			return nil, err
+		}
+		rootNode, _, err = client.selectTerm(scheduleListViewPath, &term)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	form, err := parsePSForm(rootNode)
+	if err != nil {
+		return nil, err
+	}
+	formAction, err := client.resolveFormAction(form.action)
+	if err != nil {
+		return nil, err
+	}
+	stateNum, err := form.stateNum()
+	if err != nil {
+		return nil, err
+	}
+	sid := form.values.Get("ICSID")
+	if sid == "" {
+		return nil, errors.New("could not find ICSID")
+	}
+
+	client.authLock.RLock()
+	return &classDetailSession{
+		client:     client,
+		clone:      client != c,
+		unlock:     client.authLock.RUnlock,
+		formAction: formAction,
+		sid:        sid,
+		stateNum:   stateNum,
+	}, nil
+}
+
+// fetch gets the class detail page for the component at the given index in the schedule, then
+// returns to the schedule so that the next component can be fetched.
+//
+// If the page cannot be parsed, parseErr is set, while problems with optional parts of the page are
+// reported to warnings. If a request fails, err is set and the session should no longer be used.
+func (s *classDetailSession) fetch(sectionAction string, component *Component, wantDetail bool,
+	warnings *warningCollector) (courseOpen bool, parseErr, err error) {
+	postData := generateClassDetailForm(s.sid, s.stateNum, sectionAction)
+	s.client.limiter.wait()
+	res, err := s.client.client.PostForm(s.formAction, postData)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		return
+	}
+	courseOpen, parseErr = parseClassDetailResponse(res.Body, component, wantDetail,
+		warnings)
+
+	postData = generateClassDetailBackForm(s.sid, s.stateNum+1)
+	s.client.limiter.wait()
+	res, err = s.client.client.PostForm(s.formAction, postData)
+	if res != nil {
+		defer res.Body.Close()
+	}
+
+	s.stateNum += 2
+	return
+}
+
+// close releases the session. Sessions which use a cloned Client are logged out.
+func (s *classDetailSession) close() {
+	s.unlock()
+	if s.clone {
+		s.client.logout()
+	}
+}
+
+// cloneSession creates a new Client with the same credentials and rate limiter as this one, and
+// authenticates it. The new Client has a separate PeopleSoft session.
+func (c *Client) cloneSession() (*Client, error) {
+	if c.guest {
+		clone := NewClient("", "", c.uni)
+		clone.guest = true
+		clone.limiter = c.limiter
+		return clone, nil
+	}
+	clone := NewClient(c.username, c.password, c.uni)
+	clone.limiter = c.limiter
+	if err := clone.Authenticate(); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// logout ends the client's PeopleSoft session. It is used for sessions created by cloneSession, so
+// that they do not linger on the server until they time out. Errors are ignored.
+func (c *Client) logout() {
+	if c.guest {
+		return
+	}
+	c.limiter.wait()
+	res, _ := c.client.Get(c.rootURL() + logoutPath)
+	if res != nil {
+		res.Body.Close()
+	}
+}
+
 // generateClassDetailBackForm generates the POST values needed for requests between class detail
-// requests.
-func generateClassDetailBackForm(icsid string, sectionIndex int) url.Values {
+// requests. The stateNum argument is the ICStateNum to send.
+func generateClassDetailBackForm(icsid string, stateNum int) url.Values {
 	postData := url.Values{}
 	for _, f := range []string{"ICFocus", "ICFind", "ICAddCount", "ICAPPCLSDATA"} {
 		postData.Add(f, "")
@@ -81,7 +333,7 @@ func generateClassDetailBackForm(icsid string, sectionIndex int) url.Values {
 	postData.Add("ICNAVTYPEDROPDOWN", "0")
 	postData.Add("ICType", "Panel")
 	postData.Add("ICElementNum", "0")
-	postData.Add("ICStateNum", strconv.Itoa(sectionIndex*2+1))
+	postData.Add("ICStateNum", strconv.Itoa(stateNum))
 	postData.Add("ICAction", "CLASS_SRCH_WRK2_SSR_PB_CLOSE")
 	postData.Add("ICXPos", "0")
 	postData.Add("ICYPos", "0")
@@ -106,8 +358,9 @@ func generateClassDetailBackForm(icsid string, sectionIndex int) url.Values {
 	return postData
 }
 
-// generateClassDetailForm generates the POST values for extended component information.
-func generateClassDetailForm(icsid string, sectionIndex int) url.Values {
+// generateClassDetailForm generates the POST values for extended component information. The stateNum
+// argument is the ICStateNum to send.
+func generateClassDetailForm(icsid string, stateNum int, sectionAction string) url.Values {
 	postData := url.Values{}
 	for _, f := range []string{"ICFocus", "ICFind", "ICAddCount", "ICAPPCLSDATA"} {
 		postData.Add(f, "")
@@ -119,8 +372,8 @@ func generateClassDetailForm(icsid string, sectionIndex int) url.Values {
 	postData.Add("ICNAVTYPEDROPDOWN", "0")
 	postData.Add("ICType", "Panel")
 	postData.Add("ICElementNum", "0")
-	postData.Add("ICStateNum", strconv.Itoa(sectionIndex*2))
-	postData.Add("ICAction", "MTG_SECTION$"+strconv.Itoa(sectionIndex))
+	postData.Add("ICStateNum", strconv.Itoa(stateNum))
+	postData.Add("ICAction", sectionAction)
 	postData.Add("ICXPos", "0")
 	postData.Add("ICYPos", "0")
 	postData.Add("ResponsetoDiffFrame", "-1")
@@ -144,48 +397,72 @@ func generateClassDetailForm(icsid string, sectionIndex int) url.Values {
 	return postData
 }
 
-// parseCurrentSchedule parses the courses from the schedule list view page.
+// parseSchedule parses the courses from the schedule list view page.
 //
-// If fetchMoreInfo is true, this will perform a request for each component to find out information
-// about it.
-func parseSchedule(rootNode *html.Node) ([]Course, error) {
+// Parse failures are reported to the warningCollector. In lenient mode, courses which cannot be
+// parsed at all are skipped, while components and fields which cannot be parsed are left empty.
+func parseSchedule(rootNode *html.Node, warnings *warningCollector) ([]Course, error) {
 	courseTables := scrape.FindAll(rootNode, scrape.ByClass("PSGROUPBOXWBO"))
 	result := make([]Course, 0, len(courseTables))
 	for _, classTable := range courseTables {
-		println("found course")
-
 		titleElement, ok := scrape.Find(classTable, scrape.ByClass("PAGROUPDIVIDER"))
 		if !ok {
 			// This will occur at least once, since the filter options are a PSGROUPBOXWBO.
 			continue
 		}
 
+		var course Course
+		course.Name = nodeInnerText(titleElement)
+		course.Department, course.Number = parseCourseTitle(course.Name)
+
 		infoTables := scrape.FindAll(classTable, scrape.ByClass("PSLEVEL3GRIDNBO"))
 		if len(infoTables) != 2 {
-			return nil, errors.New("expected exactly 2 info tables but found " +
-				strconv.Itoa(len(infoTables)))
+			err := warnings.add(ParseWarning{
+				Course: course.Name,
+				Err: errors.New("expected exactly 2 info tables but found " +
+					strconv.Itoa(len(infoTables))),
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
 		}
 
 		courseInfoTable := infoTables[0]
-		course, err := parseCourseInfoTable(courseInfoTable)
-		if err != nil {
-			return nil, err
+		if err := parseCourseInfoTable(courseInfoTable, &course); err != nil {
+			if err := warnings.add(ParseWarning{Course: course.Name, Err: err}); err != nil {
+				return nil, err
+			}
 		}
 
-		// NOTE: there isn't really a standard way to parse the department/number.
-		course.Name = nodeInnerText(titleElement)
-
 		componentsInfoTable := infoTables[1]
-		componentMaps, err := tableEntriesAsMaps(componentsInfoTable)
+		componentsGrid, err := ParseGrid(componentsInfoTable)
 		if err != nil {
-			return nil, err
-		}
-		course.Components = make([]Component, len(componentMaps))
-		for i, componentMap := range componentMaps {
-			course.Components[i], err = parseComponentInfoMap(componentMap)
-			if err != nil {
+			if err := warnings.add(ParseWarning{Course: course.Name, Err: err}); err != nil {
 				return nil, err
 			}
+			componentsGrid = &Grid{}
+		}
+		course.Components = make([]Component, len(componentsGrid.Rows))
+		for i, componentMap := range componentsGrid.Maps() {
+			var fieldErrs []fieldError
+			course.Components[i], fieldErrs = parseComponentInfoMap(componentMap)
+			course.Components[i].Instructors = parseInstructorCell(componentsGrid.Cell(i,
+				"Instructor"))
+			course.Components[i].sectionAction = gridRowAction(componentsGrid.Rows[i],
+				sectionActionPrefix)
+			for _, fieldErr := range fieldErrs {
+				err := warnings.add(ParseWarning{
+					Course:    course.Name,
+					Component: course.Components[i].Section,
+					Field:     fieldErr.field,
+					Value:     fieldErr.value,
+					Err:       fieldErr.err,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		result = append(result, course)
@@ -193,54 +470,85 @@ func parseSchedule(rootNode *html.Node) ([]Course, error) {
 	return result, nil
 }
 
-// parseComponentInfoMap processes a row from a courses's components and returns a Component with
-// all the available information.
-func parseComponentInfoMap(infoMap map[string]string) (component Component, err error) {
-	component.ClassNumber, err = strconv.Atoi(infoMap["Class Nbr"])
-	if err != nil {
-		return
-	}
-
-	weeklyTimes, err := ParseWeeklyTimes(infoMap["Days & Times"])
-	if err != nil {
-		return
-	} else {
-		component.WeeklyTimes = *weeklyTimes
+// gridRowAction returns the id of the first link in a grid row whose id starts with the given
+// prefix, or "" if there is none.
+func gridRowAction(row []*GridCell, prefix string) string {
+	for _, cell := range row {
+		for _, link := range cell.Links {
+			if strings.HasPrefix(link.ID, prefix) {
+				return link.ID
+			}
+		}
 	}
+	return ""
+}
 
-	startEndComps := strings.Split(infoMap["Start/End Date"], " - ")
-	if len(startEndComps) != 2 {
-		err = errors.New("invalid start/end date: " + infoMap["Start/End Date"])
+// parseCourseTitle extracts the department and number from a course title like
+// "CS 2110 - Object-Oriented Programming". If the title is not in this format, it returns empty
+// strings, since there isn't really a standard way to parse the department/number.
+func parseCourseTitle(title string) (department, number string) {
+	dashIndex := strings.Index(title, " - ")
+	if dashIndex < 0 {
 		return
 	}
-	if component.StartDate, err = ParseDate(startEndComps[0]); err != nil {
-		return
-	}
-	if component.EndDate, err = ParseDate(startEndComps[1]); err != nil {
+	fields := strings.Fields(title[:dashIndex])
+	if len(fields) != 2 {
 		return
 	}
+	return fields[0], fields[1]
+}
 
+// parseComponentInfoMap processes a row from a courses's components and returns a Component with
+// all the available information. Fields which cannot be parsed are left empty, and the errors are
+// returned. Instructors are left to the caller, since parseInstructorCell needs the cell's links.
+func parseComponentInfoMap(infoMap map[string]string) (component Component, errs []fieldError) {
 	component.Section = infoMap["Section"]
 	component.Room = infoMap["Room"]
 	component.Type = ParseComponentType(infoMap["Component"])
 
-	component.Instructors = strings.Split(infoMap["Instructor"], ",")
-	for i, instructor := range component.Instructors {
-		component.Instructors[i] = strings.TrimSpace(instructor)
+	var err error
+	if component.ClassNumber, err = strconv.Atoi(infoMap["Class Nbr"]); err != nil {
+		errs = append(errs, fieldError{"Class Nbr", infoMap["Class Nbr"], err})
+	}
+
+	if weeklyTimes, err := ParseWeeklyTimes(infoMap["Days & Times"]); err != nil {
+		errs = append(errs, fieldError{"Days & Times", infoMap["Days & Times"], err})
+	} else {
+		component.WeeklyTimes = *weeklyTimes
+	}
+
+	if startDate, endDate, err := parseDateRange(infoMap["Start/End Date"]); err != nil {
+		errs = append(errs, fieldError{"Start/End Date", infoMap["Start/End Date"], err})
+	} else {
+		component.StartDate, component.EndDate = startDate, endDate
 	}
 
 	return
 }
 
-// parseCourseInfoTable takes a table with general course fields and turns it into a Course. This
+// parseDateRange parses a range of dates like "08/27/2015 - 12/08/2015".
+func parseDateRange(str string) (start, end Date, err error) {
+	comps := strings.Split(str, " - ")
+	if len(comps) != 2 {
+		err = errors.New("invalid start/end date: " + str)
+		return
+	}
+	if start, err = ParseDate(comps[0]); err != nil {
+		return
+	}
+	end, err = ParseDate(comps[1])
+	return
+}
+
+// parseCourseInfoTable takes a table with general course fields and fills them in on a Course. This
 // will not fill in certain fields (i.e. the components and name of the course).
-func parseCourseInfoTable(table *html.Node) (course Course, err error) {
+func parseCourseInfoTable(table *html.Node, course *Course) error {
 	infoMaps, err := tableEntriesAsMaps(table)
 	if err != nil {
-		return
+		return err
 	}
 	if len(infoMaps) != 1 {
-		return course, errors.New("expected exactly 1 row in the course info table but got " +
+		return errors.New("expected exactly 1 row in the course info table but got " +
 			strconv.Itoa(len(infoMaps)))
 	}
 	infoMap := infoMaps[0]
@@ -252,74 +560,13 @@ func parseCourseInfoTable(table *html.Node) (course Course, err error) {
 		course.Units, _ = strconv.ParseFloat(unitsStr, -1)
 	}
 	course.Status = ParseEnrollmentStatus(infoMap["Status"])
-
-	return
-}
-
-// parseExtraComponentInfo parses the "Class Detail" page for a component.
-func parseExtraComponentInfo(body io.Reader, component *Component) (courseOpen bool, err error) {
-	nodes, err := html.ParseFragment(body, nil)
-	if err != nil {
-		return
-	}
-	if len(nodes) != 1 {
-		return false, errors.New("invalid number of root elements")
-	}
-
-	openStatus, ok := scrape.Find(nodes[0], scrape.ById("SSR_CLS_DTL_WRK_SSR_DESCRSHORT"))
-	if !ok {
-		return false, errors.New("open status not found")
-	}
-	courseOpen = (nodeInnerText(openStatus) == "Open")
-
-	availTable, ok := scrape.Find(nodes[0], scrape.ById("ACE_SSR_CLS_DTL_WRK_GROUP3"))
-	if !ok {
-		return courseOpen, errors.New("could not find availability info")
-	}
-
-	rows := scrape.FindAll(availTable, scrape.ByTag(atom.Tr))
-	if len(rows) != 7 {
-		return courseOpen, errors.New("invalid number of rows in availability table")
-	}
-
-	var availability ClassAvailability
-
-	cols := nodesWithAlignAttribute(scrape.FindAll(rows[2], scrape.ByTag(atom.Td)))
-	if len(cols) != 2 {
-		return courseOpen, errors.New("expected 2 aligned columns in row 2")
-	}
-	availability.Capacity, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[0])))
-	if err != nil {
-		return
-	}
-	availability.WaitListCapacity, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[1])))
-	if err != nil {
-		return
-	}
-
-	cols = nodesWithAlignAttribute(scrape.FindAll(rows[4], scrape.ByTag(atom.Td)))
-	if len(cols) != 2 {
-		return courseOpen, errors.New("expected 2 aligned columns in row 4")
-	}
-	availability.EnrollmentTotal, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[0])))
-	if err != nil {
-		return
-	}
-	availability.WaitListTotal, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[1])))
-	if err != nil {
-		return
-	}
-
-	cols = nodesWithAlignAttribute(scrape.FindAll(rows[6], scrape.ByTag(atom.Td)))
-	if len(cols) != 1 {
-		return courseOpen, errors.New("expected 1 aligned column in row 6")
-	}
-	availability.AvailableSeats, err = strconv.Atoi(strings.TrimSpace(nodeInnerText(cols[0])))
-	if err != nil {
-		return
+	if positionStr := infoMap["Waitlist Position"]; positionStr != "" {
+		position, err := strconv.Atoi(positionStr)
+		if err != nil {
+			return errors.New("invalid waitlist position: " + positionStr)
+		}
+		course.WaitlistPosition = position
 	}
 
-	component.ClassAvailability = &availability
-
-	return
+	return nil
 }
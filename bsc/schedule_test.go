@@ -0,0 +1,153 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSchedulePage = `<html><body>
+<table class="PSGROUPBOXWBO"><tr><td>Filter options</td></tr></table>
+<table class="PSGROUPBOXWBO">
+<tr><td class="PAGROUPDIVIDER">CS 2110 - Object-Oriented Programming</td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO">
+<tr><th>Status</th><th>Units</th><th>Grading</th></tr>
+<tr><td>Enrolled</td><td>3.00</td><td>Letter</td></tr>
+</table></td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO">
+<tr><th>Class Nbr</th><th>Section</th><th>Component</th><th>Days &amp; Times</th><th>Room</th>
+<th>Instructor</th><th>Start/End Date</th></tr>
+<tr><td>1234</td><td>001</td><td>Lecture</td><td>MoWeFr 10:10AM - 11:00AM</td>
+<td>Olin Hall 155</td><td><a href="mailto:jd1@cornell.edu">Jane Doe</a>,<br>Doe, John</td>
+<td>08/27/2015 - 12/08/2015</td></tr>
+<tr><td>1235</td><td>201</td><td>Discussion</td><td>Tu 2:30PM - 3:20PM</td>
+<td>Phillips 101</td><td>Staff</td><td>08/27/2015 - bad</td></tr>
+</table></td></tr>
+</table>
+<table class="PSGROUPBOXWBO">
+<tr><td class="PAGROUPDIVIDER">MATH 2940 - Linear Algebra</td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO">
+<tr><th>Status</th><th>Units</th><th>Grading</th></tr>
+<tr><td>Enrolled</td><td>4.00</td><td>Letter</td></tr>
+</table></td></tr>
+</table>
+</body></html>`
+
+func TestParseScheduleLenient(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testSchedulePage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parseSchedule(root, &warningCollector{}); err == nil {
+		t.Error("strict mode should fail on a bad date")
+	}
+
+	warnings := &warningCollector{lenient: true}
+	courses, err := parseSchedule(root, warnings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 1 {
+		t.Fatal("expected 1 course but got", len(courses))
+	}
+	course := courses[0]
+	if course.Department != "CS" || course.Number != "2110" || course.Units != 3 {
+		t.Error("unexpected course:", course)
+	}
+	if len(course.Components) != 2 || course.Components[1].ClassNumber != 1235 {
+		t.Fatal("unexpected components:", course.Components)
+	}
+
+	instructors := course.Components[0].Instructors
+	if len(instructors) != 2 || instructors[0].Email != "jd1@cornell.edu" ||
+		instructors[1].FirstName != "John" || instructors[1].Role != InstructorRoleSecondary {
+		t.Error("unexpected instructors:", instructors)
+	}
+	if len(course.Components[1].Instructors) != 0 {
+		t.Error("staff placeholder should have no instructors")
+	}
+
+	if len(warnings.warnings) != 2 {
+		t.Fatal("expected 2 warnings but got", warnings.warnings)
+	}
+	dateWarning := warnings.warnings[0]
+	if dateWarning.Component != "201" || dateWarning.Field != "Start/End Date" ||
+		dateWarning.Value != "08/27/2015 - bad" {
+		t.Error("unexpected date warning:", dateWarning)
+	}
+	if warnings.warnings[1].Course != "MATH 2940 - Linear Algebra" {
+		t.Error("unexpected course warning:", warnings.warnings[1])
+	}
+}
+
+func TestParseCourseInfoTableWaitlist(t *testing.T) {
+	page := `<html><body><table>
+<tr><th>Status</th><th>Units</th><th>Grading</th><th>Waitlist Position</th></tr>
+<tr><td>Waitlisted</td><td>4.00</td><td>Letter</td><td>7</td></tr>
+</table></body></html>`
+	root, err := parseHTMLDocument(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, ok := findTableWithHeader(root, "Status")
+	if !ok {
+		t.Fatal("missing table")
+	}
+	var course Course
+	if err := parseCourseInfoTable(table, &course); err != nil {
+		t.Fatal(err)
+	}
+	if course.Status != EnrollmentStatusWaitlisted || course.WaitlistPosition != 7 {
+		t.Error("unexpected course:", course)
+	}
+}
+
+func TestParseScheduleSectionActions(t *testing.T) {
+	page := `<html><body>
+<table class="PSGROUPBOXWBO">
+<tr><td class="PAGROUPDIVIDER">PHYS 1112 - Physics I: Mechanics</td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO"><tr><th>Status</th></tr><tr><td>Enrolled</td></tr>
+</table></td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO"><tr><th>Notes</th></tr><tr><td>None</td></tr>
+</table></td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO">
+<tr><th>Class Nbr</th><th>Section</th></tr>
+<tr><td>4001</td><td><a id="MTG_SECTION$0">001</a></td></tr>
+</table></td></tr>
+</table>
+<table class="PSGROUPBOXWBO">
+<tr><td class="PAGROUPDIVIDER">CS 2110 - Object-Oriented Programming</td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO">
+<tr><th>Status</th><th>Units</th><th>Grading</th></tr>
+<tr><td>Enrolled</td><td>3.00</td><td>Letter</td></tr>
+</table></td></tr>
+<tr><td><table class="PSLEVEL3GRIDNBO">
+<tr><th>Class Nbr</th><th>Section</th><th>Component</th><th>Days &amp; Times</th><th>Room</th>
+<th>Instructor</th><th>Start/End Date</th></tr>
+<tr><td>1234</td><td><a id="MTG_SECTION$1">001</a></td><td>Lecture</td>
+<td>MoWeFr 10:10AM - 11:00AM</td><td>Olin Hall 155</td><td>Staff</td>
+<td>08/27/2015 - 12/08/2015</td></tr>
+<tr><td>1235</td><td>201</td><td>Discussion</td><td>Tu 2:30PM - 3:20PM</td>
+<td>Phillips 101</td><td>Staff</td><td>08/27/2015 - 12/08/2015</td></tr>
+</table></td></tr>
+</table>
+</body></html>`
+	root, err := parseHTMLDocument(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	courses, err := parseSchedule(root, &warningCollector{lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 1 || len(courses[0].Components) != 2 {
+		t.Fatal("unexpected courses:", courses)
+	}
+	components := courses[0].Components
+	if components[0].sectionAction != "MTG_SECTION$1" {
+		t.Error("unexpected section action:", components[0].sectionAction)
+	}
+	if components[1].sectionAction != "" {
+		t.Error("rows without a link should have no section action")
+	}
+}
@@ -0,0 +1,247 @@
+package bsc
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// termSelectAction is the ICAction of the "Continue" button on PeopleSoft's term selection pages.
+const termSelectAction = "DERIVED_SSS_SCT_SSR_PB_GO"
+
+// termChangeAction is the ICAction of the "Change Term" button on pages which show a selected term.
+const termChangeAction = "DERIVED_SSS_SCT_SSS_TERM_LINK"
+
+// A Term represents an academic term (e.g. "Fall 2015") for a given career and institution.
+type Term struct {
+	// Code is PeopleSoft's identifier for the term (its "STRM"), such as "2158". Some term
+	// selection pages do not expose it, in which case it is empty.
+	Code string
+
+	Description string
+	Career      string
+	Institution string
+}
+
+// String returns a human-readable description of the term.
+func (t Term) String() string {
+	parts := make([]string, 0, 3)
+	for _, s := range []string{t.Description, t.Career, t.Institution} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// matches returns true if two terms refer to the same term. Codes are compared if both terms have
+// them, since descriptions may not be unique.
+func (t Term) matches(other Term) bool {
+	if t.Code != "" && other.Code != "" {
+		return t.Code == other.Code
+	}
+	return t.Description == other.Description && t.Career == other.Career &&
+		t.Institution == other.Institution
+}
+
+// A termOption is a term along with the radio button that selects it on a term selection page.
+type termOption struct {
+	Term
+	radioName  string
+	radioValue string
+}
+
+// ListTerms returns the terms for which the user has a schedule in Student Center.
+func (c *Client) ListTerms() ([]Term, error) {
+	root, err := c.fetchPage(scheduleListViewPath)
+	if err != nil {
+		return nil, err
+	}
+	return listTermsOnPage(root)
+}
+
+// listTermsOnPage returns the terms on a term selection page.
+//
+// If a student only has one term, PeopleSoft skips the selection page. In that case, the page's
+// header is used to determine the single term.
+func listTermsOnPage(root *html.Node) ([]Term, error) {
+	options := parseTermOptions(root)
+	if len(options) == 0 {
+		term, ok := parseSelectedTermHeader(root)
+		if !ok {
+			return nil, errors.New("could not find term selection or term header")
+		}
+		return []Term{term}, nil
+	}
+	terms := make([]Term, len(options))
+	for i, option := range options {
+		terms[i] = option.Term
+	}
+	return terms, nil
+}
+
+// selectTerm loads a page which may ask the user to select a term and chooses the given term. It
+// returns the page that PeopleSoft shows after the term has been selected, along with the term
+// that was selected.
+//
+// If term is nil, the first term in the list is chosen. If the page already shows a different term,
+// the term is changed. An error is returned if the page shows neither a term list nor a term.
+func (c *Client) selectTerm(page string, term *Term) (*html.Node, *Term, error) {
+	root, err := c.fetchPage(page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := parseTermOptions(root)
+	if len(options) == 0 {
+		current, ok := parseSelectedTermHeader(root)
+		if !ok {
+			return nil, nil, errors.New("could not find term selection or term header")
+		} else if term == nil || term.matches(current) {
+			return root, &current, nil
+		}
+
+		// PeopleSoft remembers the last term chosen, so the page may already show a different
+		// term. The "Change Term" button leads back to the term selection page.
+		changeAction, ok := findAction(root, termChangeAction)
+		if !ok {
+			return nil, nil, errors.New("term not available: " + term.String())
+		}
+		form, err := parsePSForm(root)
+		if err != nil {
+			return nil, nil, err
+		}
+		if root, err = c.submitForm(form, form.submitValues(changeAction)); err != nil {
+			return nil, nil, err
+		}
+		if options = parseTermOptions(root); len(options) == 0 {
+			if msg := pageErrorMessage(root); msg != "" {
+				return nil, nil, errors.New(msg)
+			}
+			return nil, nil, errors.New("could not find term selection")
+		}
+	}
+
+	var chosen *termOption
+	if term == nil {
+		chosen = &options[0]
+	} else {
+		for i := range options {
+			if options[i].matches(*term) {
+				chosen = &options[i]
+				break
+			}
+		}
+		if chosen == nil {
+			return nil, nil, errors.New("term not available: " + term.String())
+		}
+	}
+
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := form.submitValues(termSelectAction)
+	values.Set(chosen.radioName, chosen.radioValue)
+
+	result, err := c.submitForm(form, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, &chosen.Term, nil
+}
+
+// parseTermOptions finds the term radio buttons on a term selection page.
+func parseTermOptions(root *html.Node) []termOption {
+	radios := scrape.FindAll(root, func(node *html.Node) bool {
+		return node.DataAtom == atom.Input &&
+			strings.ToLower(getNodeAttribute(node, "type")) == "radio" &&
+			strings.HasPrefix(getNodeAttribute(node, "name"), "SSR_DUMMY_RECV1$sels$")
+	})
+
+	res := make([]termOption, 0, len(radios))
+	for _, radio := range radios {
+		option := termOption{
+			radioName:  getNodeAttribute(radio, "name"),
+			radioValue: getNodeAttribute(radio, "value"),
+		}
+		row, ok := scrape.FindParent(radio, scrape.ByTag(atom.Tr))
+		if !ok {
+			continue
+		}
+		index := option.radioValue
+		option.Description = spanTextByID(row, "TERM_CAR$"+index)
+		option.Career = spanTextByID(row, "CAREER$"+index)
+		option.Institution = spanTextByID(row, "INSTITUTION$"+index)
+		option.Code = spanTextByID(row, "STRM$"+index)
+
+		// Some universities lay out the grid without the usual IDs, in which case the columns are
+		// Select, Term, Career, and Institution.
+		if cells := scrape.FindAll(row, scrape.ByTag(atom.Td)); option.Description == "" &&
+			len(cells) > 1 {
+			texts := make([]string, 0, len(cells))
+			for _, cell := range cells[1:] {
+				texts = append(texts, strings.TrimSpace(nodeInnerText(cell)))
+			}
+			for i, field := range []*string{&option.Description, &option.Career,
+				&option.Institution} {
+				if i < len(texts) {
+					*field = texts[i]
+				}
+			}
+		}
+		res = append(res, option)
+	}
+	return res
+}
+
+// parseSelectedTermHeader reads the term from the header that PeopleSoft shows once a term has
+// been selected, which looks like "Fall 2015 | Undergraduate | Cornell University".
+func parseSelectedTermHeader(root *html.Node) (term Term, ok bool) {
+	header, ok := scrape.Find(root, byIDPrefix("DERIVED_REGFRM1_SSR_STDNTKEY_DESCR"))
+	if !ok {
+		return
+	}
+	parts := strings.Split(nodeInnerText(header), "|")
+	for i, field := range []*string{&term.Description, &term.Career, &term.Institution} {
+		if i < len(parts) {
+			*field = strings.TrimSpace(parts[i])
+		}
+	}
+	return term, term.Description != ""
+}
+
+// spanTextByID returns the trimmed inner text of the element with the given ID, or "" if no such
+// element exists.
+func spanTextByID(root *html.Node, id string) string {
+	if node, ok := scrape.Find(root, scrape.ById(id)); ok {
+		return strings.TrimSpace(nodeInnerText(node))
+	}
+	return ""
+}
+
+// setTermSelect sets a <select> of terms, found by prefix, to the option matching a term's code
+// or description. The code is preferred, but it is often unknown, and PeopleSoft's blank
+// placeholder option never matches.
+func setTermSelect(form *psForm, values url.Values, prefix string, term Term) error {
+	name, ok := form.fieldName(prefix)
+	if !ok {
+		return errors.New("could not find field: " + prefix)
+	}
+	for _, str := range []string{term.Code, term.Description} {
+		if str == "" {
+			continue
+		}
+		for _, option := range form.options[name] {
+			if option.value != "" && (option.value == str || option.text == str) {
+				values.Set(name, option.value)
+				return nil
+			}
+		}
+	}
+	return errors.New("term not available: " + term.String())
+}
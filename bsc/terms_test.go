@@ -0,0 +1,116 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testTermSelectionPage = `<html><body>
+<form name="win0" class="PSForm" method="post" action="SSR_SSENRL_LIST.GBL">
+<input type="hidden" name="ICSID" id="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" id="ICStateNum" value="1">
+<table>
+<tr><th>Select</th><th>Term</th><th>Career</th><th>Institution</th></tr>
+<tr>
+<td><input type="radio" name="SSR_DUMMY_RECV1$sels$0" value="0"></td>
+<td><span id="TERM_CAR$0">Fall 2015</span></td>
+<td><span id="CAREER$0">Undergraduate</span></td>
+<td><span id="INSTITUTION$0">Cornell University</span></td>
+</tr>
+<tr>
+<td><input type="radio" name="SSR_DUMMY_RECV1$sels$0" value="1"></td>
+<td>Spring 2016</td><td>Undergraduate</td><td>Cornell University</td>
+</tr>
+</table>
+</form>
+</body></html>`
+
+func TestListTermsOnPage(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testTermSelectionPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	terms, err := listTermsOnPage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Term{
+		{Description: "Fall 2015", Career: "Undergraduate", Institution: "Cornell University"},
+		{Description: "Spring 2016", Career: "Undergraduate", Institution: "Cornell University"},
+	}
+	if len(terms) != len(expected) {
+		t.Fatal("expected", len(expected), "terms but got", len(terms))
+	}
+	for i, term := range terms {
+		if term != expected[i] {
+			t.Error("term", i, "should be", expected[i], "but got", term)
+		}
+	}
+
+	form, err := parsePSForm(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := form.fieldName("SSR_DUMMY_RECV1$sels$"); !ok || name != "SSR_DUMMY_RECV1$sels$0" {
+		t.Error("unexpected radio field:", name)
+	}
+	if form.values.Get("ICSID") != "abc" {
+		t.Error("unexpected ICSID:", form.values.Get("ICSID"))
+	}
+}
+
+func TestListTermsOnSelectedPage(t *testing.T) {
+	page := `<html><body><span id="DERIVED_REGFRM1_SSR_STDNTKEY_DESCR$11$">` +
+		`Fall 2015 | Graduate | Cornell University</span></body></html>`
+	root, err := parseHTMLDocument(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	terms, err := listTermsOnPage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Term{Description: "Fall 2015", Career: "Graduate", Institution: "Cornell University"}
+	if len(terms) != 1 || terms[0] != expected {
+		t.Error("unexpected terms:", terms)
+	}
+}
+
+func TestSetTermSelect(t *testing.T) {
+	page := `<html><body>
+<form name="win0" action="SSR_SSENRL_LIST.GBL">
+<input type="hidden" name="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" value="1">
+<select name="CLASS_SRCH_WRK2_STRM$35$">
+<option value=""></option>
+<option value="2158">Fall 2015</option>
+<option value="2161">Spring 2016</option>
+</select>
+</form>
+</body></html>`
+	root, err := parseHTMLDocument(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const field = "CLASS_SRCH_WRK2_STRM$35$"
+
+	for _, term := range []Term{{Code: "2161"}, {Description: "Spring 2016"},
+		{Code: "2161", Description: "Spring"}} {
+		values := form.submitValues("")
+		if err := setTermSelect(form, values, "CLASS_SRCH_WRK2_STRM$", term); err != nil {
+			t.Error(err)
+		} else if values.Get(field) != "2161" {
+			t.Errorf("%v selected %q", term, values.Get(field))
+		}
+	}
+
+	values := form.submitValues("")
+	if err := setTermSelect(form, values, "CLASS_SRCH_WRK2_STRM$",
+		Term{Description: "Fall 2016"}); err == nil {
+		t.Error("expected an error for a missing term but got", values.Get(field))
+	}
+}
@@ -0,0 +1,456 @@
+package bsc
+
+import (
+	"errors"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var unofficialTranscriptPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSS_TSRQST_UNOFF.GBL"
+
+const (
+	transcriptTypeField    = "DERIVED_SSTSRPT_TSCRPT_TYPE3"
+	transcriptSubmitAction = "GO"
+)
+
+var (
+	transcriptTermPattern    = regexp.MustCompile(`^(Fall|Spring|Summer|Winter|Autumn)\s+\d{4}\b`)
+	transcriptSubjectPattern = regexp.MustCompile(`^[A-Z&]{2,8}$`)
+	transcriptNumberPattern  = regexp.MustCompile(`^\d{3,5}[A-Z]?$`)
+	transcriptColumnPattern  = regexp.MustCompile(`\s{2,}`)
+)
+
+// A Transcript is the user's unofficial transcript.
+type Transcript struct {
+	Terms          []TranscriptTerm
+	TransferCredit []TransferCredit
+
+	// Cumulative is the last set of cumulative statistics on the transcript. It is nil if the
+	// transcript does not show any.
+	Cumulative *GradeStatistics
+}
+
+// A TranscriptTerm is a term on a Transcript.
+type TranscriptTerm struct {
+	// Term is the term the courses were taken in. Only its Description is set.
+	Term    Term
+	Courses []TranscriptCourse
+
+	// Statistics and Cumulative are the totals printed after the term's courses. Either may be
+	// nil if the transcript does not show them.
+	Statistics *GradeStatistics
+	Cumulative *GradeStatistics
+}
+
+// TransferCredit is a group of courses which were accepted from another institution.
+type TransferCredit struct {
+	// Source is the institution or exam (e.g. "Advanced Placement") which the credit came from.
+	Source  string
+	Courses []TranscriptCourse
+}
+
+// A RepeatStatus indicates how a repeated course counts towards the GPA.
+type RepeatStatus int
+
+const (
+	RepeatNone RepeatStatus = iota
+
+	// RepeatIncluded marks a repeated course which still counts towards the GPA.
+	RepeatIncluded
+
+	// RepeatExcluded marks a repeated course which does not count towards the GPA, usually
+	// because a later attempt replaced it.
+	RepeatExcluded
+)
+
+// String returns a human-readable version of the RepeatStatus.
+func (r RepeatStatus) String() string {
+	names := map[RepeatStatus]string{
+		RepeatNone:     "None",
+		RepeatIncluded: "Included",
+		RepeatExcluded: "Excluded",
+	}
+	if name, ok := names[r]; ok {
+		return name
+	} else {
+		return "Other"
+	}
+}
+
+// A TranscriptCourse is a course on a Transcript.
+type TranscriptCourse struct {
+	Department  string
+	Number      string
+	Description string
+
+	// Attempted and Earned are the units attempted and earned. For transfer credit, both are the
+	// number of units accepted.
+	Attempted float64
+	Earned    float64
+
+	Grade string
+
+	// GradePoints is the total number of grade points earned, i.e. the grade's value multiplied by
+	// the units.
+	GradePoints float64
+
+	// Repeat is the repeat note printed with the course (e.g. "Repeated - Excluded from GPA"). It
+	// is empty if the course was not repeated.
+	Repeat       string
+	RepeatStatus RepeatStatus
+}
+
+// FetchUnofficialTranscript requests the user's unofficial transcript and parses it.
+//
+// The report is requested with the first report type that the Student Center offers. The report
+// may be shown in the page itself or linked to as an HTML or text file; PDF reports are not
+// supported.
+func (c *Client) FetchUnofficialTranscript() (*Transcript, error) {
+	root, err := c.fetchPage(unofficialTranscriptPath)
+	if err != nil {
+		return nil, err
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	values := form.submitValues(transcriptSubmitAction)
+	if name, ok := form.fieldName(transcriptTypeField); ok && values.Get(name) == "" {
+		for _, option := range form.options[name] {
+			if option.value != "" {
+				values.Set(name, option.value)
+				break
+			}
+		}
+	}
+	if root, err = c.submitForm(form, values); err != nil {
+		return nil, err
+	}
+	if msg := pageErrorMessage(root); msg != "" {
+		return nil, errors.New(msg)
+	}
+
+	// Some schools show the transcript on the request page itself. Term names alone are not enough
+	// to go on, since they also appear in drop-downs and headings.
+	lines := reportLines(root)
+	if transcript := parseTranscriptLines(lines); transcript.hasCourses() {
+		return transcript, nil
+	}
+	reportURL, ok := transcriptReportURL(root)
+	if !ok {
+		return nil, errors.New("could not find transcript report")
+	}
+	if lines, err = c.fetchReportLines(reportURL); err != nil {
+		return nil, err
+	}
+	transcript := parseTranscriptLines(lines)
+	if len(transcript.Terms) == 0 && len(transcript.TransferCredit) == 0 {
+		return nil, errors.New("could not parse transcript report")
+	}
+	return transcript, nil
+}
+
+// hasCourses returns true if the transcript lists at least one course.
+func (t *Transcript) hasCourses() bool {
+	for _, term := range t.Terms {
+		if len(term.Courses) > 0 {
+			return true
+		}
+	}
+	for _, credit := range t.TransferCredit {
+		if len(credit.Courses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// transcriptReportURL finds the link or frame through which PeopleSoft serves a generated report.
+func transcriptReportURL(root *html.Node) (string, bool) {
+	node, ok := scrape.Find(root, func(node *html.Node) bool {
+		var link string
+		switch node.DataAtom {
+		case atom.A:
+			link = getNodeAttribute(node, "href")
+		case atom.Iframe, atom.Frame:
+			link = getNodeAttribute(node, "src")
+		default:
+			return false
+		}
+		return strings.Contains(strings.ToLower(link), "psreports")
+	})
+	if !ok {
+		return "", false
+	}
+	if node.DataAtom == atom.A {
+		return getNodeAttribute(node, "href"), true
+	}
+	return getNodeAttribute(node, "src"), true
+}
+
+// fetchReportLines downloads a generated report and returns its lines of text. Text reports are
+// split into lines as-is, and HTML reports are converted with reportLines.
+func (c *Client) fetchReportLines(reportURL string) ([]string, error) {
+	absURL, err := c.resolveFormAction(reportURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.limiter.wait()
+	c.authLock.RLock()
+	resp, err := c.client.Get(absURL)
+	c.authLock.RUnlock()
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "pdf"):
+		return nil, errors.New("PDF transcript reports are not supported")
+	case strings.HasPrefix(contentType, "text/plain"):
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return strings.Split(string(body), "\n"), nil
+	}
+	root, err := parseHTMLDocument(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return reportLines(root), nil
+}
+
+// reportLines converts an HTML report into lines of text like those of a text report. Table rows
+// become lines with their cells separated by two spaces, so that both formats can be parsed as
+// columns. Preformatted text keeps its own lines.
+func reportLines(root *html.Node) []string {
+	var lines []string
+	var current []string
+	flush := func() {
+		if line := strings.TrimSpace(strings.Join(current, " ")); line != "" {
+			lines = append(lines, line)
+		}
+		current = nil
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Script, atom.Style:
+			return
+		case atom.Pre:
+			flush()
+			lines = append(lines, strings.Split(nodeInnerText(n), "\n")...)
+			return
+		case atom.Tr:
+			if _, nested := scrape.Find(n, scrape.ByTag(atom.Table)); !nested {
+				flush()
+				var cells []string
+				for _, cell := range rowCells(n) {
+					if text := strings.Join(strings.Fields(nodeInnerText(cell)), " "); text != "" {
+						cells = append(cells, text)
+					}
+				}
+				current = []string{strings.Join(cells, "  ")}
+				flush()
+				return
+			}
+		}
+		if n.Type == html.TextNode {
+			if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+				current = append(current, text)
+			}
+			return
+		}
+
+		block := n.DataAtom != 0 && !isInlineElement(n.DataAtom)
+		if block {
+			flush()
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if block {
+			flush()
+		}
+	}
+	walk(root)
+	flush()
+	return lines
+}
+
+func isInlineElement(a atom.Atom) bool {
+	switch a {
+	case atom.A, atom.Span, atom.B, atom.I, atom.Em, atom.Strong, atom.Font, atom.Label, atom.Nobr:
+		return true
+	}
+	return false
+}
+
+// parseTranscriptLines parses the lines of a transcript report. Lines which are not recognized,
+// such as headings and the student's address, are skipped.
+func parseTranscriptLines(lines []string) *Transcript {
+	transcript := &Transcript{}
+	var courses *[]TranscriptCourse
+	var term *TranscriptTerm
+
+	for _, rawLine := range lines {
+		line := strings.TrimSpace(rawLine)
+		upper := strings.ToUpper(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(upper, "TRANSFER CREDIT"):
+			source := ""
+			if idx := strings.Index(upper, " FROM "); idx >= 0 {
+				source = strings.TrimSpace(line[idx+len(" FROM "):])
+			}
+			transcript.TransferCredit = append(transcript.TransferCredit,
+				TransferCredit{Source: source})
+			term = nil
+			courses = &transcript.TransferCredit[len(transcript.TransferCredit)-1].Courses
+		case transcriptTermPattern.MatchString(line):
+			transcript.Terms = append(transcript.Terms, TranscriptTerm{
+				Term: Term{Description: transcriptTermPattern.FindString(line)},
+			})
+			term = &transcript.Terms[len(transcript.Terms)-1]
+			courses = &term.Courses
+		case strings.HasPrefix(upper, "TERM GPA") || strings.HasPrefix(upper, "CUM GPA") ||
+			strings.HasPrefix(upper, "CUMULATIVE GPA"):
+			stats := parseTranscriptStatistics(line)
+			if stats == nil {
+				continue
+			}
+			if strings.HasPrefix(upper, "TERM") {
+				if term != nil {
+					term.Statistics = stats
+				}
+			} else {
+				transcript.Cumulative = stats
+				if term != nil {
+					term.Cumulative = stats
+				}
+			}
+		case strings.Contains(upper, "REPEAT") && courses != nil && len(*courses) > 0 &&
+			parseTranscriptCourse(line, term == nil) == nil:
+			setCourseRepeat(&(*courses)[len(*courses)-1], line)
+		case courses != nil:
+			if course := parseTranscriptCourse(line, term == nil); course != nil {
+				*courses = append(*courses, *course)
+			}
+		}
+	}
+
+	return transcript
+}
+
+// parseTranscriptCourse parses a course line like
+//
+//	CS      2110  Object-Oriented Prog  3.00  3.00  A-  11.100
+//
+// For transfer credit, only one unit column is expected. It returns nil if the line is not a
+// course.
+func parseTranscriptCourse(line string, transfer bool) *TranscriptCourse {
+	fields := transcriptColumnPattern.Split(strings.TrimSpace(line), -1)
+	if len(fields) > 0 {
+		if parts := strings.Fields(fields[0]); len(parts) == 2 {
+			fields = append(parts, fields[1:]...)
+		}
+	}
+	if len(fields) < 4 || !transcriptSubjectPattern.MatchString(fields[0]) ||
+		!transcriptNumberPattern.MatchString(fields[1]) {
+		return nil
+	}
+	course := &TranscriptCourse{Department: fields[0], Number: fields[1]}
+
+	// The description is everything up to the first number.
+	rest := fields[2:]
+	var description []string
+	for len(rest) > 0 {
+		if _, err := strconv.ParseFloat(rest[0], 64); err == nil {
+			break
+		}
+		description = append(description, rest[0])
+		rest = rest[1:]
+	}
+	course.Description = strings.Join(description, " ")
+
+	var units []float64
+	for len(rest) > 0 && len(units) < 2 {
+		num, err := strconv.ParseFloat(rest[0], 64)
+		if err != nil {
+			break
+		}
+		units = append(units, num)
+		rest = rest[1:]
+	}
+	switch {
+	case len(units) == 1 && transfer:
+		course.Attempted, course.Earned = units[0], units[0]
+	case len(units) == 2:
+		course.Attempted, course.Earned = units[0], units[1]
+	default:
+		return nil
+	}
+
+	if len(rest) > 0 {
+		course.Grade = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		if points, err := strconv.ParseFloat(rest[0], 64); err == nil {
+			course.GradePoints = points
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 0 {
+		setCourseRepeat(course, strings.Join(rest, " "))
+	}
+	return course
+}
+
+// setCourseRepeat records a repeat note for a course.
+func setCourseRepeat(course *TranscriptCourse, note string) {
+	course.Repeat = strings.TrimSpace(note)
+	lower := strings.ToLower(course.Repeat)
+	if strings.Contains(lower, "exclud") || strings.Contains(lower, "not in gpa") {
+		course.RepeatStatus = RepeatExcluded
+	} else {
+		course.RepeatStatus = RepeatIncluded
+	}
+}
+
+// parseTranscriptStatistics parses a line like
+//
+//	TERM GPA:  3.471  TERM TOTALS:  7.00  7.00  7.00  24.300
+//
+// which gives the GPA followed by the units attempted, units earned, GPA units, and grade points.
+// It returns nil if the line has too few numbers.
+func parseTranscriptStatistics(line string) *GradeStatistics {
+	var nums []float64
+	for _, field := range strings.Fields(line) {
+		if num, err := strconv.ParseFloat(field, 64); err == nil {
+			nums = append(nums, num)
+		}
+	}
+	if len(nums) < 5 {
+		return nil
+	}
+	return &GradeStatistics{
+		GPA:         nums[0],
+		UnitsTaken:  nums[1],
+		UnitsPassed: nums[2],
+		GPAUnits:    nums[3],
+		GradePoints: nums[4],
+	}
+}
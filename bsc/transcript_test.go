@@ -0,0 +1,160 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+const testTranscriptText = `                     Unofficial Transcript
+Name: Jane Doe
+
+Transfer Credit from Tompkins Cortland CC
+Applied Toward Undergraduate Program
+MATH    1110  Calculus I                      4.00   T
+
+Fall 2014
+Program: College of Engineering
+Course        Description                  Attempted  Earned  Grade  Points
+CS      2110  Object-Oriented Programming       3.00    3.00  A-     11.100
+PHYS    1112  Physics I: Mechanics              4.00    0.00  F       0.000
+PE      1100  Rock Climbing                     1.00    1.00  S       0.000
+TERM GPA:  1.586  TERM TOTALS:   8.00   4.00   7.00  11.100
+CUM GPA:   1.586  CUM TOTALS:   12.00   8.00   7.00  11.100
+
+Spring 2015
+PHYS    1112  Physics I: Mechanics              4.00    4.00  B+     13.200
+        Repeated: Included in GPA
+TERM GPA:  3.300  TERM TOTALS:   4.00   4.00   4.00  13.200
+CUM GPA:   2.209  CUM TOTALS:   16.00  12.00  11.00  24.300
+`
+
+const testTranscriptHTML = `<html><body>
+<h2>Unofficial Transcript</h2>
+<p>Fall 2014</p>
+<table>
+<tr><th>Course</th><th>Description</th><th>Attempted</th><th>Earned</th><th>Grade</th>
+<th>Points</th><th>Repeat</th></tr>
+<tr><td>CS 2110</td><td>Object-Oriented Programming</td><td>3.00</td><td>3.00</td>
+<td>A-</td><td>11.100</td><td></td></tr>
+<tr><td>PHYS 1112</td><td>Physics I: Mechanics</td><td>4.00</td><td>0.00</td><td>F</td>
+<td>0.000</td><td>Repeated - Excluded from GPA</td></tr>
+<tr><td>Term GPA:</td><td>3.700</td><td>Term Totals:</td><td>7.00</td><td>3.00</td>
+<td>3.00</td><td>11.100</td></tr>
+</table>
+</body></html>`
+
+func TestParseTranscriptText(t *testing.T) {
+	transcript := parseTranscriptLines(strings.Split(testTranscriptText, "\n"))
+	if len(transcript.TransferCredit) != 1 {
+		t.Fatal("expected 1 transfer credit group but got", len(transcript.TransferCredit))
+	}
+	transfer := transcript.TransferCredit[0]
+	if transfer.Source != "Tompkins Cortland CC" || len(transfer.Courses) != 1 ||
+		transfer.Courses[0].Earned != 4 || transfer.Courses[0].Grade != "T" {
+		t.Error("unexpected transfer credit:", transfer)
+	}
+
+	if len(transcript.Terms) != 2 {
+		t.Fatal("expected 2 terms but got", len(transcript.Terms))
+	}
+	fall := transcript.Terms[0]
+	if fall.Term.Description != "Fall 2014" || len(fall.Courses) != 3 {
+		t.Fatal("unexpected fall term:", fall)
+	}
+	cs := fall.Courses[0]
+	if cs.Department != "CS" || cs.Number != "2110" ||
+		cs.Description != "Object-Oriented Programming" || cs.Attempted != 3 ||
+		cs.Grade != "A-" || cs.GradePoints != 11.1 {
+		t.Error("unexpected course:", cs)
+	}
+	if fall.Statistics == nil || fall.Statistics.GPA != 1.586 || fall.Statistics.UnitsTaken != 8 {
+		t.Error("unexpected term statistics:", fall.Statistics)
+	}
+
+	spring := transcript.Terms[1]
+	if len(spring.Courses) != 1 || spring.Courses[0].RepeatStatus != RepeatIncluded {
+		t.Error("repeat was not recorded:", spring.Courses)
+	}
+	if transcript.Cumulative == nil || transcript.Cumulative.GradePoints != 24.3 {
+		t.Error("unexpected cumulative statistics:", transcript.Cumulative)
+	}
+}
+
+func TestParseTranscriptHTML(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testTranscriptHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transcript := parseTranscriptLines(reportLines(root))
+	if len(transcript.Terms) != 1 {
+		t.Fatal("expected 1 term but got", len(transcript.Terms))
+	}
+	term := transcript.Terms[0]
+	if len(term.Courses) != 2 {
+		t.Fatal("expected 2 courses but got", len(term.Courses))
+	}
+	if term.Courses[1].Number != "1112" || term.Courses[1].RepeatStatus != RepeatExcluded {
+		t.Error("unexpected repeated course:", term.Courses[1])
+	}
+	if term.Statistics == nil || term.Statistics.GPA != 3.7 || term.Statistics.GPAUnits != 3 {
+		t.Error("unexpected term statistics:", term.Statistics)
+	}
+}
+
+func TestParseTranscriptRequestPage(t *testing.T) {
+	page := `<html><body>
+<h2>View Unofficial Transcript</h2>
+<select><option>Fall 2015</option><option>Spring 2016</option></select>
+<a href="/report.pdf">View Report</a>
+</body></html>`
+	root, err := parseHTMLDocument(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transcript := parseTranscriptLines(reportLines(root)); transcript.hasCourses() {
+		t.Error("request page should not be taken for a transcript:", transcript.Terms)
+	}
+	if !parseTranscriptLines(strings.Split(testTranscriptText, "\n")).hasCourses() {
+		t.Error("transcript should have courses")
+	}
+}
+
+func TestGradeScaleRecompute(t *testing.T) {
+	transcript := parseTranscriptLines(strings.Split(testTranscriptText, "\n"))
+	calc := StandardGradeScale().Recompute(transcript)
+	if len(calc.Terms) != 2 {
+		t.Fatal("expected 2 terms but got", len(calc.Terms))
+	}
+	if calc.Terms[0].GPAUnits != 7 || calc.Terms[0].UnitsTaken != 8 {
+		t.Error("unexpected fall statistics:", calc.Terms[0])
+	}
+	if calc.Cumulative.GPAUnits != 11 || differs(calc.Cumulative.GradePoints, 24.3) ||
+		calc.Cumulative.UnitsPassed != 12 {
+		t.Error("unexpected cumulative statistics:", calc.Cumulative)
+	}
+	if len(calc.Discrepancies) != 0 {
+		t.Error("unexpected discrepancies:", calc.Discrepancies)
+	}
+
+	transcript.Terms[1].Courses[0].GradePoints = 12
+	transcript.Terms[1].Statistics.GPA = 3.0
+	calc = StandardGradeScale().Recompute(transcript)
+	if len(calc.Discrepancies) != 2 {
+		t.Fatal("expected 2 discrepancies but got", calc.Discrepancies)
+	}
+	if calc.Discrepancies[0].Course == nil || calc.Discrepancies[0].Field != "Grade Points" {
+		t.Error("unexpected course discrepancy:", calc.Discrepancies[0])
+	}
+	if d := calc.Discrepancies[1]; d.Field != "GPA" || d.Cumulative || d.Term != "Spring 2015" {
+		t.Error("unexpected term discrepancy:", d)
+	}
+}
+
+func TestEngineGradeScale(t *testing.T) {
+	if _, ok := EngineGradeScale(testMinimalEngine{})["A+"]; ok {
+		t.Error("engines without a grade scale should use the standard scale")
+	}
+	if EngineGradeScale(CornellEngine{})["A+"] != 4.3 {
+		t.Error("engine's grade scale was not used")
+	}
+}
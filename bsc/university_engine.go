@@ -1,12 +1,79 @@
 package bsc
 
+import "time"
+
 // A UniversityEngine implements university-specific methods for their respective Student Centers.
 type UniversityEngine interface {
 	Authenticate(client *Client) error
 	RootURL() string
 }
 
+// A GuestEngine is a UniversityEngine which supports guest clients (see NewGuestClient). PeopleSoft
+// serves guest pages from a different root URL than student pages.
+type GuestEngine interface {
+	UniversityEngine
+	GuestRootURL() string
+}
+
+// A LocationEngine is a UniversityEngine with its own rules for parsing the room text shown for a
+// component. Engines which do not implement LocationEngine use the package-level ParseLocation.
+type LocationEngine interface {
+	UniversityEngine
+	ParseLocation(room string) Location
+}
+
+// EngineParseLocation parses room text with the engine's ParseLocation if it is a LocationEngine,
+// or with the package-level ParseLocation otherwise.
+func EngineParseLocation(uni UniversityEngine, room string) Location {
+	if locationEngine, ok := uni.(LocationEngine); ok {
+		return locationEngine.ParseLocation(room)
+	}
+	return ParseLocation(room)
+}
+
+// A TimeZoneEngine is a UniversityEngine which specifies the time zone in which its Student Center
+// shows dates and times. Engines which do not implement TimeZoneEngine are assumed to use US
+// Eastern time.
+type TimeZoneEngine interface {
+	UniversityEngine
+	TimeZone() *time.Location
+}
+
+// EngineTimeZone returns the engine's TimeZone if it is a TimeZoneEngine, or US Eastern time
+// otherwise.
+func EngineTimeZone(uni UniversityEngine) *time.Location {
+	if timeZoneEngine, ok := uni.(TimeZoneEngine); ok {
+		return timeZoneEngine.TimeZone()
+	}
+	return easternTime()
+}
+
+// A GradeScaleEngine is a UniversityEngine which specifies the grade points its university assigns
+// to each letter grade. Engines which do not implement GradeScaleEngine use StandardGradeScale.
+type GradeScaleEngine interface {
+	UniversityEngine
+	GradeScale() GradeScale
+}
+
+// EngineGradeScale returns the engine's GradeScale if it is a GradeScaleEngine, or
+// StandardGradeScale otherwise.
+func EngineGradeScale(uni UniversityEngine) GradeScale {
+	if gradeScaleEngine, ok := uni.(GradeScaleEngine); ok {
+		return gradeScaleEngine.GradeScale()
+	}
+	return StandardGradeScale()
+}
+
 var EnginesByName map[string]UniversityEngine = map[string]UniversityEngine{
 	"uri":     URIEngine{},
 	"cornell": CornellEngine{},
 }
+
+// easternTime returns the US Eastern time zone. If the time zone database is not available, it
+// falls back on Eastern Standard Time.
+func easternTime() *time.Location {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		return loc
+	}
+	return time.FixedZone("EST", -5*60*60)
+}
@@ -3,10 +3,13 @@ package bsc
 import (
 	"errors"
 	"net/url"
+	"strings"
+	"time"
 )
 
 var uriAuthURL string = "https://appsaprod.uri.edu:9503/psp/sahrprod_m2/?cmd=login&languageCd=ENG"
 var uriRootURL string = "https://appsaprod.uri.edu:9503/psc/sahrprod_m2"
+var uriGuestRootURL string = "https://appsaprod.uri.edu:9503/psc/sahrprod_m2_public"
 
 // URIEngine implements UniversityEngine for University of Rhode Island's eCampus
 type URIEngine struct{}
@@ -45,7 +48,38 @@ func (_ URIEngine) Authenticate(client *Client) error {
 	return nil
 }
 
+// ParseLocation parses URI's room text, which gives building codes like "CBLS 100".
+func (_ URIEngine) ParseLocation(room string) Location {
+	loc := ParseLocation(room)
+	if loc.BuildingName != "" && loc.Room != "" {
+		// Building codes are occasionally lower-case or followed by a wing, as in "Chafee A 271".
+		fields := strings.Fields(loc.BuildingName)
+		loc.BuildingCode = strings.ToUpper(fields[0])
+		loc.BuildingName = ""
+		if len(fields) > 1 {
+			loc.Room = strings.Join(fields[1:], " ") + " " + loc.Room
+		}
+	}
+	return loc
+}
+
+// TimeZone returns the time zone of URI's Kingston campus.
+func (_ URIEngine) TimeZone() *time.Location {
+	return easternTime()
+}
+
+// GradeScale returns URI's grade scale, which is the standard 4.0 scale.
+func (_ URIEngine) GradeScale() GradeScale {
+	return StandardGradeScale()
+}
+
 // RootURL returns the URL prefix that serves iframe content from URI's PeopleSoft system
 func (_ URIEngine) RootURL() string {
 	return uriRootURL
 }
+
+// GuestRootURL returns the URL prefix of URI's public PeopleSoft site, which serves class search
+// and the course catalog without a login.
+func (_ URIEngine) GuestRootURL() string {
+	return uriGuestRootURL
+}
@@ -0,0 +1,88 @@
+package bsc
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A WaitlistSample is a wait list position observed at a certain time.
+type WaitlistSample struct {
+	Time     time.Time `json:"time"`
+	Position int       `json:"position"`
+}
+
+// A WaitlistHistory records a user's wait list positions across several schedule fetches, so that
+// the user can see whether or not they are moving up. It can be saved and loaded as JSON.
+type WaitlistHistory struct {
+	// Courses maps course keys to samples in chronological order. A key identifies the term, the
+	// course, and its class numbers (e.g. "fall 2015 CS 2110 12345 12350"), since the same course
+	// can be waitlisted in more than one term or section.
+	Courses map[string][]WaitlistSample `json:"courses"`
+}
+
+// NewWaitlistHistory creates an empty WaitlistHistory.
+func NewWaitlistHistory() *WaitlistHistory {
+	return &WaitlistHistory{Courses: map[string][]WaitlistSample{}}
+}
+
+// LoadWaitlistHistory reads a WaitlistHistory which was written by Save.
+func LoadWaitlistHistory(r io.Reader) (*WaitlistHistory, error) {
+	history := NewWaitlistHistory()
+	if err := json.NewDecoder(r).Decode(history); err != nil {
+		return nil, err
+	}
+	if history.Courses == nil {
+		history.Courses = map[string][]WaitlistSample{}
+	}
+	return history, nil
+}
+
+// Save writes the history as JSON.
+func (h *WaitlistHistory) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(h)
+}
+
+// Record adds a sample for every waitlisted course in a term's schedule which shows a wait list
+// position.
+func (h *WaitlistHistory) Record(t time.Time, term Term, courses []Course) {
+	for _, course := range courses {
+		if course.Status != EnrollmentStatusWaitlisted || course.WaitlistPosition == 0 {
+			continue
+		}
+		key := waitlistKey(term, course)
+		h.Courses[key] = append(h.Courses[key], WaitlistSample{t, course.WaitlistPosition})
+	}
+}
+
+// Samples returns the recorded samples for a course in a term, oldest first.
+func (h *WaitlistHistory) Samples(term Term, course Course) []WaitlistSample {
+	return h.Courses[waitlistKey(term, course)]
+}
+
+// Trend returns how many places the user has moved up on a course's wait list since the first
+// recorded sample. It is negative if the user has moved down. The second return value is false if
+// fewer than two samples have been recorded.
+func (h *WaitlistHistory) Trend(term Term, course Course) (int, bool) {
+	samples := h.Samples(term, course)
+	if len(samples) < 2 {
+		return 0, false
+	}
+	return samples[0].Position - samples[len(samples)-1].Position, true
+}
+
+// waitlistKey identifies a course in a WaitlistHistory. Terms are identified by their descriptions,
+// since some pages do not show term codes.
+func waitlistKey(term Term, course Course) string {
+	termKey := strings.ToLower(strings.Join(strings.Fields(term.Description), " "))
+	if termKey == "" {
+		termKey = term.Code
+	}
+	parts := []string{termKey, course.Department, course.Number}
+	for _, component := range course.Components {
+		parts = append(parts, strconv.Itoa(component.ClassNumber))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,72 @@
+package bsc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWaitlistHistory(t *testing.T) {
+	term := Term{Code: "2158", Description: "Fall 2015"}
+	course := Course{Department: "CS", Number: "4820", Status: EnrollmentStatusWaitlisted,
+		Components: []Component{{ClassNumber: 12345}}}
+	enrolled := Course{Department: "CS", Number: "2110", Status: EnrollmentStatusEnrolled}
+
+	history := NewWaitlistHistory()
+	start := time.Date(2015, time.August, 20, 12, 0, 0, 0, time.UTC)
+	for i, position := range []int{12, 9, 4} {
+		course.WaitlistPosition = position
+		history.Record(start.Add(time.Duration(i)*time.Hour), term, []Course{course, enrolled})
+	}
+
+	var buf bytes.Buffer
+	if err := history.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadWaitlistHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.Samples(term, enrolled)) != 0 {
+		t.Error("enrolled courses should not be recorded")
+	}
+	samples := loaded.Samples(term, course)
+	if len(samples) != 3 || !samples[2].Time.Equal(start.Add(2*time.Hour)) {
+		t.Fatal("unexpected samples:", samples)
+	}
+	if trend, ok := loaded.Trend(term, course); !ok || trend != 8 {
+		t.Error("expected a trend of 8 but got", trend, ok)
+	}
+}
+
+func TestWaitlistHistoryKeys(t *testing.T) {
+	fall := Term{Code: "2158", Description: "Fall 2015"}
+	spring := Term{Code: "2161", Description: "Spring 2016"}
+	course := Course{Department: "CS", Number: "4820", Status: EnrollmentStatusWaitlisted,
+		WaitlistPosition: 5, Components: []Component{{ClassNumber: 12345}}}
+	otherSection := course
+	otherSection.WaitlistPosition = 2
+	otherSection.Components = []Component{{ClassNumber: 12346}}
+
+	history := NewWaitlistHistory()
+	history.Record(time.Now(), fall, []Course{course, otherSection})
+	history.Record(time.Now(), spring, []Course{course})
+
+	for _, term := range []Term{fall, spring} {
+		if samples := history.Samples(term, course); len(samples) != 1 || samples[0].Position != 5 {
+			t.Error("unexpected samples for", term, samples)
+		}
+	}
+	if samples := history.Samples(fall, otherSection); len(samples) != 1 ||
+		samples[0].Position != 2 {
+		t.Error("unexpected samples for the other section:", samples)
+	}
+
+	// The schedule page's header only shows the term's description.
+	headerTerm := Term{Description: "Fall 2015", Career: "Undergraduate"}
+	history.Record(time.Now(), headerTerm, []Course{course})
+	if samples := history.Samples(fall, course); len(samples) != 2 {
+		t.Error("terms with and without codes should share samples:", samples)
+	}
+}
@@ -0,0 +1,66 @@
+package bsc
+
+import "strings"
+
+// A ParseWarning describes part of a page which could not be parsed.
+//
+// In lenient mode, parse failures are collected as ParseWarnings instead of causing the entire
+// page to fail. In strict mode, the first ParseWarning is returned as an error.
+type ParseWarning struct {
+	// Course is the name of the course to which the unparsable data belongs.
+	Course string
+
+	// Component is the section of the component to which the unparsable data belongs, or "" if
+	// the data belongs to the course as a whole.
+	Component string
+
+	// Field is the name of the field which could not be parsed, such as "Start/End Date". It is ""
+	// if an entire course or component could not be parsed.
+	Field string
+
+	// Value is the raw text which could not be parsed.
+	Value string
+
+	Err error
+}
+
+// Error generates a human-readable description of the warning.
+func (p ParseWarning) Error() string {
+	location := make([]string, 0, 3)
+	for _, s := range []string{p.Course, p.Component, p.Field} {
+		if s != "" {
+			location = append(location, s)
+		}
+	}
+	msg := strings.Join(location, ": ")
+	if p.Value != "" {
+		msg += " (" + p.Value + ")"
+	}
+	if msg == "" {
+		return p.Err.Error()
+	}
+	return msg + ": " + p.Err.Error()
+}
+
+// A fieldError is an error parsing a specific field of a table row.
+type fieldError struct {
+	field string
+	value string
+	err   error
+}
+
+// warningCollector gathers ParseWarnings in lenient mode. In strict mode, it turns the first
+// warning into an error.
+type warningCollector struct {
+	lenient  bool
+	warnings []ParseWarning
+}
+
+// add records a warning. It returns a non-nil error if the warning should abort parsing.
+func (w *warningCollector) add(warning ParseWarning) error {
+	if !w.lenient {
+		return warning
+	}
+	w.warnings = append(w.warnings, warning)
+	return nil
+}
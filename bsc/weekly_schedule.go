@@ -0,0 +1,319 @@
+package bsc
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+)
+
+var weeklySchedulePath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SSR_SS_WEEK.GBL"
+
+// A Meeting is a single, dated meeting of a course component.
+type Meeting struct {
+	Department string
+	Number     string
+	Section    string
+	Type       ComponentType
+	Date       Date
+	Start      TimeOfDay
+	End        TimeOfDay
+	Room       string
+}
+
+// FetchWeeklySchedule downloads the meetings shown on the "My Weekly Schedule" page for the week
+// containing the given date. Weeks start on Monday.
+//
+// Unlike the schedule list view, the weekly schedule reflects one-off changes such as cancelled
+// classes and makeup sessions. Use ReconcileWeek to compare it against a schedule.
+func (c *Client) FetchWeeklySchedule(week Date) ([]Meeting, error) {
+	root, err := c.fetchPage(weeklySchedulePath)
+	if err != nil {
+		return nil, err
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	refreshAction, ok := findAction(root, "DERIVED_CLASS_S_SSR_REFRESH_CAL")
+	if !ok {
+		return nil, errors.New("could not find refresh calendar button")
+	}
+
+	weekStart := startOfWeek(week)
+	values := form.submitValues(refreshAction)
+	fields := map[string]string{
+		"DERIVED_CLASS_S_START_DT":           weekStart.String(),
+		"DERIVED_CLASS_S_MEETING_TIME_START": "12:00AM",
+		"DERIVED_CLASS_S_MEETING_TIME_END":   "11:59PM",
+	}
+	for prefix, value := range fields {
+		if name, ok := form.fieldName(prefix); ok {
+			values.Set(name, value)
+		} else {
+			return nil, errors.New("missing weekly schedule field: " + prefix)
+		}
+	}
+
+	page, err := c.submitForm(form, values)
+	if err != nil {
+		return nil, err
+	}
+	return parseWeeklySchedule(page, weekStart)
+}
+
+// parseWeeklySchedule parses the calendar grid on the weekly schedule page. The weekStart argument
+// is the Monday of the week the page shows, since the grid's column headers do not include years.
+func parseWeeklySchedule(root *html.Node, weekStart Date) ([]Meeting, error) {
+	table, ok := scrape.Find(root, scrape.ById("WEEKLY_SCHED_HTMLAREA"))
+	if !ok {
+		return nil, errors.New("could not find weekly schedule grid")
+	}
+	grid, err := ParseGrid(table)
+	if err != nil {
+		return nil, err
+	}
+
+	columnDates := make([]*Date, len(grid.Headers))
+	for i, header := range grid.Headers {
+		if weekday, ok := parseWeekdayName(header); ok {
+			offset := (int(weekday) - int(time.Monday) + 7) % 7
+			date := weekStart.AddDays(offset)
+			columnDates[i] = &date
+		}
+	}
+
+	var res []Meeting
+	for rowIndex, row := range grid.Rows {
+		for col, cell := range row {
+			// Meetings span several rows of the grid, but should only be counted once.
+			if columnDates[col] == nil || cell.Row != rowIndex || cell.Column != col {
+				continue
+			}
+			if meeting, ok := parseWeeklyMeetingCell(cell, *columnDates[col]); ok {
+				res = append(res, meeting)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// parseWeeklyMeetingCell parses a cell from the weekly schedule grid, which contains lines like
+// "CS 2110 - 001", "Lecture", "10:10AM - 11:00AM", and "Olin Hall 155".
+// It returns false if the cell does not describe a meeting.
+func parseWeeklyMeetingCell(cell *GridCell, date Date) (meeting Meeting, ok bool) {
+	lines := cell.Lines
+	if len(lines) < 3 {
+		return
+	}
+
+	classParts := strings.Split(lines[0], " - ")
+	if len(classParts) != 2 {
+		return
+	}
+	courseFields := strings.Fields(classParts[0])
+	if len(courseFields) != 2 {
+		return
+	}
+	meeting.Department, meeting.Number = courseFields[0], courseFields[1]
+	meeting.Section = strings.TrimSpace(classParts[1])
+	meeting.Type = ParseComponentType(lines[1])
+
+	timeParts := strings.Split(lines[2], " - ")
+	if len(timeParts) != 2 {
+		return
+	}
+	var err error
+	if meeting.Start, err = ParseTimeOfDay(strings.TrimSpace(timeParts[0])); err != nil {
+		return
+	}
+	if meeting.End, err = ParseTimeOfDay(strings.TrimSpace(timeParts[1])); err != nil {
+		return
+	}
+
+	if len(lines) > 3 {
+		meeting.Room = strings.Join(lines[3:], " ")
+	}
+	meeting.Date = date
+	return meeting, true
+}
+
+// parseWeekdayName finds the English name of a weekday (e.g. "Monday") at the start of a string.
+func parseWeekdayName(str string) (time.Weekday, bool) {
+	str = strings.TrimSpace(str)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(str, day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// startOfWeek returns the Monday on or before the given date.
+func startOfWeek(date Date) Date {
+	return date.AddDays(-((int(date.Weekday()) - int(time.Monday) + 7) % 7))
+}
+
+// A MeetingChangeKind indicates how a meeting on the weekly schedule compares to the regular
+// meeting times of a component.
+type MeetingChangeKind int
+
+const (
+	// MeetingRegular indicates a meeting which occurs as the component's weekly times predict.
+	MeetingRegular MeetingChangeKind = iota
+
+	// MeetingCancelled indicates a regular meeting which does not appear on the weekly schedule.
+	MeetingCancelled
+
+	// MeetingRescheduled indicates a meeting on the expected day with a different time or room.
+	MeetingRescheduled
+
+	// MeetingAdded indicates a meeting which is not one of the component's regular meetings, such
+	// as a makeup session.
+	MeetingAdded
+)
+
+// String returns a human-readable version of the MeetingChangeKind.
+func (m MeetingChangeKind) String() string {
+	names := map[MeetingChangeKind]string{
+		MeetingRegular:     "Regular",
+		MeetingCancelled:   "Cancelled",
+		MeetingRescheduled: "Rescheduled",
+		MeetingAdded:       "Added",
+	}
+	if name, ok := names[m]; ok {
+		return name
+	} else {
+		return "Other"
+	}
+}
+
+// A ReconciledMeeting is the result of comparing a meeting against a schedule.
+type ReconciledMeeting struct {
+	Kind MeetingChangeKind
+
+	// Meeting is the meeting from the weekly schedule. For cancelled meetings, it is the meeting
+	// that was expected to take place.
+	Meeting Meeting
+
+	// Expected is the regular meeting for rescheduled meetings, and nil otherwise.
+	Expected *Meeting
+
+	// Course and Component point into the courses passed to ReconcileWeek. They are nil if the
+	// meeting does not belong to any known course.
+	Course    *Course
+	Component *Component
+}
+
+// ReconcileWeek compares the meetings on the weekly schedule for a given week against the regular
+// meeting times of a schedule's components.
+func ReconcileWeek(courses []Course, week Date, meetings []Meeting) []ReconciledMeeting {
+	weekStart := startOfWeek(week)
+	used := make([]bool, len(meetings))
+	var res []ReconciledMeeting
+
+	for courseIndex := range courses {
+		course := &courses[courseIndex]
+		for componentIndex := range course.Components {
+			component := &course.Components[componentIndex]
+			for _, expected := range expectedMeetings(course, component, weekStart) {
+				expected := expected
+				actualIndex := findMeeting(meetings, used, expected, true)
+				if actualIndex < 0 {
+					actualIndex = findMeeting(meetings, used, expected, false)
+				}
+				reconciled := ReconciledMeeting{Course: course, Component: component}
+				if actualIndex < 0 {
+					reconciled.Kind = MeetingCancelled
+					reconciled.Meeting = expected
+				} else {
+					used[actualIndex] = true
+					reconciled.Meeting = meetings[actualIndex]
+					if sameMeetingTime(expected, meetings[actualIndex]) {
+						reconciled.Kind = MeetingRegular
+					} else {
+						reconciled.Kind = MeetingRescheduled
+						reconciled.Expected = &expected
+					}
+				}
+				res = append(res, reconciled)
+			}
+		}
+	}
+
+	for i, meeting := range meetings {
+		if used[i] {
+			continue
+		}
+		reconciled := ReconciledMeeting{Kind: MeetingAdded, Meeting: meeting}
+		reconciled.Course, reconciled.Component = findComponent(courses, meeting)
+		res = append(res, reconciled)
+	}
+
+	return res
+}
+
+// expectedMeetings generates the regular meetings of a component during the week starting on
+// weekStart.
+func expectedMeetings(course *Course, component *Component, weekStart Date) []Meeting {
+	var res []Meeting
+	for _, day := range component.WeeklyTimes.Days {
+		date := weekStart.AddDays((int(day) - int(time.Monday) + 7) % 7)
+		if date.Before(component.StartDate) || component.EndDate.Before(date) {
+			continue
+		}
+		res = append(res, Meeting{
+			Department: course.Department,
+			Number:     course.Number,
+			Section:    component.Section,
+			Type:       component.Type,
+			Date:       date,
+			Start:      component.WeeklyTimes.Start,
+			End:        component.WeeklyTimes.End,
+			Room:       component.Room,
+		})
+	}
+	return res
+}
+
+// findMeeting finds the index of an unused meeting for the same section and date as expected. If
+// exact is true, the meeting's time must also match. It returns -1 if no meeting is found.
+func findMeeting(meetings []Meeting, used []bool, expected Meeting, exact bool) int {
+	for i, meeting := range meetings {
+		if used[i] || !sameSection(meeting, expected) || meeting.Date != expected.Date {
+			continue
+		}
+		if !exact || (meeting.Start == expected.Start && meeting.End == expected.End) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findComponent finds the course and component to which a meeting belongs.
+func findComponent(courses []Course, meeting Meeting) (*Course, *Component) {
+	for courseIndex := range courses {
+		course := &courses[courseIndex]
+		if course.Department != meeting.Department || course.Number != meeting.Number {
+			continue
+		}
+		for componentIndex := range course.Components {
+			component := &course.Components[componentIndex]
+			if component.Section == meeting.Section {
+				return course, component
+			}
+		}
+	}
+	return nil, nil
+}
+
+func sameSection(m1, m2 Meeting) bool {
+	return m1.Department == m2.Department && m1.Number == m2.Number && m1.Section == m2.Section
+}
+
+func sameMeetingTime(m1, m2 Meeting) bool {
+	return m1.Start == m2.Start && m1.End == m2.End && m1.Room == m2.Room
+}
@@ -0,0 +1,88 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testWeeklySchedulePage = `<html><body><table id="WEEKLY_SCHED_HTMLAREA">
+<tr><th>Time</th><th>Monday<br>Oct 12</th><th>Tuesday<br>Oct 13</th><th>Wednesday<br>Oct 14</th></tr>
+<tr><td>10:00AM</td>
+<td rowspan="2"><span>CS 2110 - 001<br>Lecture<br>10:10AM - 11:00AM<br>Olin Hall 155</span></td>
+<td>&nbsp;</td>
+<td>&nbsp;</td>
+</tr>
+<tr><td>10:30AM</td>
+<td>&nbsp;</td>
+<td><span>CS 2110 - 001<br>Lecture<br>7:30PM - 8:20PM<br>Gates G01</span></td>
+</tr>
+</table></body></html>`
+
+func TestParseWeeklySchedule(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testWeeklySchedulePage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekStart := Date{time.October, 12, 2015}
+	meetings, err := parseWeeklySchedule(root, weekStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meetings) != 2 {
+		t.Fatal("expected 2 meetings but got", len(meetings))
+	}
+	if meetings[0].Date != weekStart || meetings[0].Room != "Olin Hall 155" ||
+		meetings[0].Start != 10*60+10 || meetings[0].Type != ComponentTypeLecture {
+		t.Error("unexpected first meeting:", meetings[0])
+	}
+	// The rowspan on Monday's cell means the second row's meeting is in Wednesday's column.
+	if meetings[1].Date != weekStart.AddDays(2) || meetings[1].Section != "001" {
+		t.Error("unexpected second meeting:", meetings[1])
+	}
+}
+
+func TestReconcileWeek(t *testing.T) {
+	courses := []Course{
+		{
+			Department: "CS",
+			Number:     "2110",
+			Components: []Component{
+				{
+					Section: "001",
+					Room:    "Olin Hall 155",
+					WeeklyTimes: WeeklyTimes{
+						Days:  []time.Weekday{time.Monday, time.Wednesday, time.Friday},
+						Start: 10*60 + 10,
+						End:   11 * 60,
+					},
+					StartDate: Date{time.August, 27, 2015},
+					EndDate:   Date{time.December, 8, 2015},
+				},
+			},
+		},
+	}
+	meetings := []Meeting{
+		{Department: "CS", Number: "2110", Section: "001", Date: Date{time.October, 12, 2015},
+			Start: 10*60 + 10, End: 11 * 60, Room: "Olin Hall 155"},
+		{Department: "CS", Number: "2110", Section: "001", Date: Date{time.October, 14, 2015},
+			Start: 19*60 + 30, End: 20*60 + 20, Room: "Gates G01"},
+		{Department: "CS", Number: "2110", Section: "001", Date: Date{time.October, 17, 2015},
+			Start: 10 * 60, End: 11 * 60, Room: "Olin Hall 155"},
+	}
+
+	res := ReconcileWeek(courses, Date{time.October, 14, 2015}, meetings)
+	expected := []MeetingChangeKind{MeetingRegular, MeetingRescheduled, MeetingCancelled,
+		MeetingAdded}
+	if len(res) != len(expected) {
+		t.Fatal("expected", len(expected), "results but got", len(res))
+	}
+	for i, kind := range expected {
+		if res[i].Kind != kind {
+			t.Error("result", i, "should be", kind, "but got", res[i].Kind)
+		}
+	}
+	if res[3].Component != &courses[0].Components[0] {
+		t.Error("added meeting was not linked to its component")
+	}
+}
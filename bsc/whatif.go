@@ -0,0 +1,297 @@
+package bsc
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yhat/scrape"
+	"golang.org/x/net/html"
+)
+
+var whatIfPath string = "/EMPLOYEE/HRMS/c/SA_LEARNER_SERVICES.SAA_SS_WHATIF.GBL"
+
+const (
+	whatIfCreateAction  = "DERIVED_SAAWHIF_SSS_CREATE_WHATIF"
+	whatIfSubmitAction  = "DERIVED_SAAWHIF_SSS_SUBMIT_REQUEST"
+	whatIfRefreshAction = "DERIVED_SAAWHIF_SSS_REFRESH"
+
+	whatIfProgramField     = "DERIVED_SAAWHIF_ACAD_PROG$"
+	whatIfPlanField        = "DERIVED_SAAWHIF_ACAD_PLAN$"
+	whatIfCatalogTermField = "DERIVED_SAAWHIF_TERM_CATALOG$"
+
+	defaultWhatIfPollInterval = 5 * time.Second
+	defaultWhatIfTimeout      = 2 * time.Minute
+)
+
+// ErrWhatIfCancelled is returned by RunWhatIf when WhatIfOptions.Cancel is closed before the
+// report is ready.
+var ErrWhatIfCancelled = errors.New("what-if report cancelled")
+
+// WhatIfOptions controls how RunWhatIf waits for a report. What-if reports are generated in the
+// background, so RunWhatIf polls for the result.
+type WhatIfOptions struct {
+	// PollInterval is the time between checks for the finished report. It defaults to 5 seconds.
+	PollInterval time.Duration
+
+	// Timeout is the longest time to wait for the report. It defaults to 2 minutes.
+	Timeout time.Duration
+
+	// Cancel may be closed to stop waiting for the report.
+	Cancel <-chan struct{}
+}
+
+// RunWhatIf runs a what-if academic requirements report, which shows how the user's courses
+// would count towards a different program and plan (e.g. "College of Engineering" and "Computer
+// Science Major") under the requirements of a catalog term. The program and plan may be given as
+// either codes or descriptions, and the catalog term may be given by code or description.
+//
+// This blocks until PeopleSoft finishes generating the report, which may take a minute, or until
+// the wait is cancelled or times out (see WhatIfOptions).
+func (c *Client) RunWhatIf(program, plan string, catalogTerm Term,
+	opts WhatIfOptions) (*AcademicRequirements, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultWhatIfPollInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultWhatIfTimeout
+	}
+
+	root, err := c.fetchPage(whatIfPath)
+	if err != nil {
+		return nil, err
+	}
+	if action, ok := findAction(root, whatIfCreateAction); ok {
+		form, err := parsePSForm(root)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = c.submitWhatIfForm(form, form.submitValues(action)); err != nil {
+			return nil, err
+		}
+	}
+
+	form, err := parsePSForm(root)
+	if err != nil {
+		return nil, err
+	}
+	submitAction, ok := findAction(root, whatIfSubmitAction)
+	if !ok {
+		return nil, errors.New("could not find what-if scenario form")
+	}
+
+	// The plans which are offered depend on the program, so changing the program refreshes the
+	// page. PeopleSoft uses the field's name as the ICAction for field changes.
+	programField, ok := form.fieldName(whatIfProgramField)
+	if !ok {
+		return nil, errors.New("could not find program field")
+	}
+	values := form.submitValues(programField)
+	if err := form.setSelect(values, programField, program); err != nil {
+		return nil, err
+	}
+	if root, err = c.submitWhatIfForm(form, values); err != nil {
+		return nil, err
+	}
+	if form, err = parsePSForm(root); err != nil {
+		return nil, err
+	}
+
+	values = form.submitValues(submitAction)
+	if err := form.setSelect(values, whatIfPlanField, plan); err != nil {
+		return nil, err
+	}
+	if err := setTermSelect(form, values, whatIfCatalogTermField, catalogTerm); err != nil {
+		return nil, err
+	}
+	if root, err = c.submitWhatIfForm(form, values); err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(opts.Timeout)
+	defer timeout.Stop()
+	for !whatIfReportReady(root) {
+		action, ok := findAction(root, whatIfRefreshAction)
+		if !ok {
+			return nil, errors.New("could not find what-if report status")
+		}
+		select {
+		case <-time.After(opts.PollInterval):
+		case <-timeout.C:
+			return nil, errors.New("what-if report did not finish")
+		case <-opts.Cancel:
+			return nil, ErrWhatIfCancelled
+		}
+		if form, err = parsePSForm(root); err != nil {
+			return nil, err
+		}
+		if root, err = c.submitWhatIfForm(form, form.submitValues(action)); err != nil {
+			return nil, err
+		}
+	}
+
+	if root, err = c.expandRequirements(root); err != nil {
+		return nil, err
+	}
+	return parseAcademicRequirements(root)
+}
+
+// submitWhatIfForm submits a form on the what-if pages and returns an error if the resulting page
+// shows one, such as when the chosen program does not exist.
+func (c *Client) submitWhatIfForm(form *psForm, values url.Values) (*html.Node, error) {
+	root, err := c.submitForm(form, values)
+	if err != nil {
+		return nil, err
+	}
+	if msg := pageErrorMessage(root); msg != "" {
+		return nil, errors.New(msg)
+	}
+	return root, nil
+}
+
+// whatIfReportReady returns true if a page contains a finished requirements report.
+func whatIfReportReady(root *html.Node) bool {
+	_, ok := scrape.Find(root, byIDPrefix(requirementGroupPrefix))
+	return ok
+}
+
+// A RequirementChangeKind is the type of a RequirementChange.
+type RequirementChangeKind int
+
+const (
+	// RequirementAdded indicates a part of the report which only appears in the second report.
+	RequirementAdded RequirementChangeKind = iota
+
+	// RequirementRemoved indicates a part of the report which only appears in the first report.
+	RequirementRemoved
+
+	// RequirementStatusChanged indicates that a part's status differs between the reports.
+	RequirementStatusChanged
+
+	// RequirementProgressChanged indicates that a part has the same status in both reports, but
+	// different unit or course counts.
+	RequirementProgressChanged
+)
+
+// String returns a human-readable version of the RequirementChangeKind.
+func (r RequirementChangeKind) String() string {
+	names := map[RequirementChangeKind]string{
+		RequirementAdded:           "Added",
+		RequirementRemoved:         "Removed",
+		RequirementStatusChanged:   "Status changed",
+		RequirementProgressChanged: "Progress changed",
+	}
+	if name, ok := names[r]; ok {
+		return name
+	} else {
+		return "Other"
+	}
+}
+
+// A RequirementChange is a difference between two academic requirements reports.
+type RequirementChange struct {
+	Kind RequirementChangeKind
+
+	// Path contains the titles leading to the changed part, starting with its RequirementGroup.
+	// It has one entry for a group, two for a requirement, and three for a line item.
+	Path []string
+
+	// Before and After are the part in the first and second report. Before is nil for added parts
+	// and After is nil for removed parts.
+	Before *RequirementInfo
+	After  *RequirementInfo
+}
+
+// DiffRequirements compares two academic requirements reports, such as the user's current report
+// and a what-if report. Parts of the reports are matched by their titles.
+//
+// Changes to parts of the second report are listed first, in the order they appear in it,
+// followed by the parts which were removed.
+func DiffRequirements(before, after *AcademicRequirements) []RequirementChange {
+	beforeParts := flattenRequirements(before)
+	beforeByKey := map[string]requirementPart{}
+	for _, part := range beforeParts {
+		beforeByKey[part.key] = part
+	}
+
+	var res []RequirementChange
+	afterKeys := map[string]bool{}
+	for _, part := range flattenRequirements(after) {
+		afterKeys[part.key] = true
+		old, ok := beforeByKey[part.key]
+		change := RequirementChange{Path: part.path, Before: old.info, After: part.info}
+		switch {
+		case !ok:
+			change.Kind = RequirementAdded
+		case old.info.Status != part.info.Status:
+			change.Kind = RequirementStatusChanged
+		case !sameCounts(old.info.Units, part.info.Units) ||
+			!sameCounts(old.info.Courses, part.info.Courses):
+			change.Kind = RequirementProgressChanged
+		default:
+			continue
+		}
+		res = append(res, change)
+	}
+	for _, part := range beforeParts {
+		if !afterKeys[part.key] {
+			res = append(res, RequirementChange{
+				Kind:   RequirementRemoved,
+				Path:   part.path,
+				Before: part.info,
+			})
+		}
+	}
+	return res
+}
+
+type requirementPart struct {
+	key  string
+	path []string
+	info *RequirementInfo
+}
+
+// flattenRequirements lists every group, requirement, and line item in a report. Each part's key
+// is made from the titles of its ancestors and itself, numbering repeated titles so that keys are
+// unique.
+func flattenRequirements(report *AcademicRequirements) []requirementPart {
+	var res []requirementPart
+	seen := map[string]int{}
+	add := func(parent []string, parentKey string, info *RequirementInfo) ([]string, string) {
+		key := parentKey + "\x00" + info.Title
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key += "#" + strconv.Itoa(n)
+		}
+		path := append(append([]string{}, parent...), info.Title)
+		res = append(res, requirementPart{key: key, path: path, info: info})
+		return path, key
+	}
+
+	for i := range report.Groups {
+		group := &report.Groups[i]
+		groupPath, groupKey := add(nil, "", &group.RequirementInfo)
+		for j := range group.Requirements {
+			requirement := &group.Requirements[j]
+			reqPath, reqKey := add(groupPath, groupKey, &requirement.RequirementInfo)
+			for k := range requirement.LineItems {
+				add(reqPath, reqKey, &requirement.LineItems[k].RequirementInfo)
+			}
+		}
+	}
+	return res
+}
+
+func sameCounts(a, b *RequirementCounts) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// String returns the titles in a change's path separated by " > ".
+func (r RequirementChange) String() string {
+	return r.Kind.String() + ": " + strings.Join(r.Path, " > ")
+}
@@ -0,0 +1,110 @@
+package bsc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWhatIfReportReady(t *testing.T) {
+	pending, err := parseHTMLDocument(strings.NewReader(`<html><body>
+<span>Report Request Status: Processing</span>
+<input type="button" id="DERIVED_SAAWHIF_SSS_REFRESH" value="Refresh">
+</body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if whatIfReportReady(pending) {
+		t.Error("pending report should not be ready")
+	}
+	done, err := parseHTMLDocument(strings.NewReader(testRequirementsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !whatIfReportReady(done) {
+		t.Error("finished report should be ready")
+	}
+}
+
+func TestDiffRequirements(t *testing.T) {
+	root, err := parseHTMLDocument(strings.NewReader(testRequirementsPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := parseAcademicRequirements(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := DiffRequirements(before, before); len(changes) != 0 {
+		t.Error("identical reports should have no changes:", changes)
+	}
+
+	// Parse again so that the reports do not share any slices.
+	after, err := parseAcademicRequirements(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after.Groups[0].Units.Taken = 32
+	after.Groups[0].Units.Needed = 8
+	after.Groups[0].Requirements[0].LineItems[0].Status = RequirementStatusNotSatisfied
+	after.Groups = after.Groups[:1]
+	after.Groups = append(after.Groups, RequirementGroup{
+		RequirementInfo: RequirementInfo{Title: "Minor in Mathematics"},
+	})
+
+	changes := DiffRequirements(before, after)
+	expected := []struct {
+		kind RequirementChangeKind
+		path string
+	}{
+		{RequirementProgressChanged, "Major in Computer Science"},
+		{RequirementStatusChanged,
+			"Major in Computer Science > Introductory Programming > CS 2110"},
+		{RequirementAdded, "Minor in Mathematics"},
+		{RequirementRemoved, "University Requirements"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatal("expected", len(expected), "changes but got", changes)
+	}
+	for i, e := range expected {
+		if changes[i].Kind != e.kind || strings.Join(changes[i].Path, " > ") != e.path {
+			t.Error("change", i, "should be", e, "but got", changes[i])
+		}
+	}
+	if changes[2].Before != nil || changes[3].After != nil {
+		t.Error("added and removed changes should only have one side")
+	}
+}
+
+func TestWhatIfCatalogTerm(t *testing.T) {
+	page := `<html><body>
+<form name="win0" action="SAA_SS_WHATIF.GBL">
+<input type="hidden" name="ICSID" value="abc">
+<input type="hidden" name="ICStateNum" value="3">
+<select name="DERIVED_SAAWHIF_TERM_CATALOG$0">
+<option value=""></option>
+<option value="2148">Fall 2014</option>
+<option value="2158">Fall 2015</option>
+</select>
+</form>
+</body></html>`
+	root, err := parseHTMLDocument(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	form, err := parsePSForm(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := form.submitValues(whatIfSubmitAction)
+	err = setTermSelect(form, values, whatIfCatalogTermField, Term{Description: "Fall 2015"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := values.Get("DERIVED_SAAWHIF_TERM_CATALOG$0"); value != "2158" {
+		t.Errorf("expected catalog term 2158 but got %q", value)
+	}
+	err = setTermSelect(form, values, whatIfCatalogTermField, Term{Description: "Fall 2016"})
+	if err == nil {
+		t.Error("expected an error for an unknown catalog term")
+	}
+}
@@ -0,0 +1,257 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/unixpickle/better-student-center/bsc"
+)
+
+// An Enroller enrolls in classes. Both *bsc.Client and *Session are Enrollers.
+//
+// Like *bsc.Client, an Enroller must not submit an enrollment unless its confirmation page lists
+// only the requested classes.
+type Enroller interface {
+	Enroll(term bsc.Term, classNumbers []int, dryRun bool) (*bsc.EnrollmentReport, error)
+	Swap(term bsc.Term, dropClassNumber, addClassNumber int, options bsc.CartOptions,
+		dryRun bool) (*bsc.EnrollmentReport, error)
+}
+
+// A Target is a class which an AutoEnroller should enroll in when it opens.
+type Target struct {
+	// ClassNumber is the class to enroll in. Unless SwapFor is set, the class must already be in
+	// the student's shopping cart.
+	ClassNumber int
+
+	// SwapFor is the class number of an enrolled class to swap for the target. PeopleSoft only
+	// drops this class if the enrollment in the target succeeds, so the student keeps it as a
+	// backup. It is 0 for a plain enrollment.
+	SwapFor int
+
+	// Options are used when swapping.
+	Options bsc.CartOptions
+}
+
+// required returns the class numbers which an attempt's confirmation page must list.
+func (t Target) required() []int {
+	return []int{t.ClassNumber}
+}
+
+// allowed returns the class numbers which an attempt's confirmation page may list in addition to
+// the required ones.
+func (t Target) allowed() []int {
+	if t.SwapFor == 0 {
+		return nil
+	}
+	return append([]int{t.SwapFor}, t.Options.RelatedClassNumbers...)
+}
+
+// A ConfirmationPolicy decides whether or not an enrollment attempt should go ahead. It is given
+// the result of a dry run of the attempt.
+type ConfirmationPolicy interface {
+	Confirm(target Target, dryRun *bsc.EnrollmentReport) bool
+}
+
+// ConfirmFunc adapts a function to the ConfirmationPolicy interface.
+type ConfirmFunc func(target Target, dryRun *bsc.EnrollmentReport) bool
+
+// Confirm calls f(target, dryRun).
+func (f ConfirmFunc) Confirm(target Target, dryRun *bsc.EnrollmentReport) bool {
+	return f(target, dryRun)
+}
+
+// AlwaysConfirm is a ConfirmationPolicy which allows every attempt. The AutoEnroller only consults
+// the policy once the dry run has been checked to list the target's classes and nothing else.
+var AlwaysConfirm ConfirmationPolicy = ConfirmFunc(func(target Target,
+	dryRun *bsc.EnrollmentReport) bool {
+	return dryRun.CheckClasses(target.required(), target.allowed()) == nil
+})
+
+// An AuditStage is a step of an enrollment attempt.
+type AuditStage string
+
+const (
+	AuditDryRun       AuditStage = "dry run"
+	AuditDeclined     AuditStage = "declined"
+	AuditSubmitted    AuditStage = "submitted"
+	AuditLimitReached AuditStage = "limit reached"
+)
+
+// An AuditEntry records one step of an enrollment attempt.
+type AuditEntry struct {
+	Time        time.Time  `json:"time"`
+	Stage       AuditStage `json:"stage"`
+	ClassNumber int        `json:"class_number"`
+	SwapFor     int        `json:"swap_for,omitempty"`
+
+	// Messages contains PeopleSoft's message for each class. Dry runs have no messages, so the
+	// classes' descriptions are used instead.
+	Messages []string `json:"messages,omitempty"`
+
+	Succeeded bool   `json:"succeeded"`
+	Error     string `json:"error,omitempty"`
+}
+
+// AutoEnrollConfig configures an AutoEnroller.
+type AutoEnrollConfig struct {
+	Term    bsc.Term
+	Targets []Target
+
+	// MaxAttempts is the maximum number of enrollment attempts the AutoEnroller makes in total.
+	// Values less than 1 are treated as 1.
+	MaxAttempts int
+
+	// Policy must be set. It is consulted before every attempt.
+	Policy ConfirmationPolicy
+
+	// AuditLog, if set, receives each AuditEntry as a line of JSON.
+	AuditLog io.Writer
+}
+
+// An AutoEnroller attempts to enroll in classes when a Watcher reports that they have opened.
+//
+// An AutoEnroller is a Notifier, so it can be added to a Watcher's Config.
+type AutoEnroller struct {
+	enroller Enroller
+	config   AutoEnrollConfig
+
+	lock      sync.Mutex
+	attempts  int
+	enrolled  map[int]bool
+	audit     []AuditEntry
+	limitSeen bool
+}
+
+// NewAutoEnroller creates an AutoEnroller. It fails if the config has no Policy.
+func NewAutoEnroller(enroller Enroller, config AutoEnrollConfig) (*AutoEnroller, error) {
+	if config.Policy == nil {
+		return nil, errors.New("auto-enroll requires a confirmation policy")
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	return &AutoEnroller{enroller: enroller, config: config, enrolled: map[int]bool{}}, nil
+}
+
+// Audit returns every step which the AutoEnroller has taken so far.
+func (a *AutoEnroller) Audit() []AuditEntry {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return append([]AuditEntry{}, a.audit...)
+}
+
+// Notify attempts to enroll in a target class if the event reports that it has opened.
+//
+// Each attempt starts with a dry run. The attempt is declined with an error unless the dry run
+// lists exactly the target's classes; otherwise, the dry run is given to the Policy, and the
+// enrollment is only submitted if the Policy confirms it. An error is returned if a request fails.
+func (a *AutoEnroller) Notify(e Event) error {
+	if e.Kind != EventOpened || e.Term != a.config.Term {
+		return nil
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	target, ok := a.target(e.ClassNumber)
+	if !ok || a.enrolled[target.ClassNumber] {
+		return nil
+	}
+	if a.attempts >= a.config.MaxAttempts {
+		if !a.limitSeen {
+			a.limitSeen = true
+			a.record(AuditEntry{Stage: AuditLimitReached, ClassNumber: target.ClassNumber,
+				SwapFor: target.SwapFor})
+		}
+		return nil
+	}
+	a.attempts++
+
+	dryRun, err := a.run(target, true, target.required())
+	a.record(auditEntry(AuditDryRun, target, dryRun, err))
+	if err != nil {
+		return err
+	}
+	if err := dryRun.CheckClasses(target.required(), target.allowed()); err != nil {
+		a.record(AuditEntry{Stage: AuditDeclined, ClassNumber: target.ClassNumber,
+			SwapFor: target.SwapFor, Error: err.Error()})
+		return err
+	}
+	if !a.config.Policy.Confirm(target, dryRun) {
+		a.record(AuditEntry{Stage: AuditDeclined, ClassNumber: target.ClassNumber,
+			SwapFor: target.SwapFor})
+		return nil
+	}
+
+	report, err := a.run(target, false, approvedClasses(dryRun))
+	entry := auditEntry(AuditSubmitted, target, report, err)
+	a.record(entry)
+	if entry.Succeeded {
+		a.enrolled[target.ClassNumber] = true
+	}
+	return err
+}
+
+func (a *AutoEnroller) target(classNumber int) (Target, bool) {
+	for _, target := range a.config.Targets {
+		if target.ClassNumber == classNumber {
+			return target, true
+		}
+	}
+	return Target{}, false
+}
+
+// run makes an enrollment attempt. The dry run and the submission are separate walks through
+// PeopleSoft, so the submission is given the classes which were approved in the dry run, and bsc
+// checks the real confirmation page against them before submitting.
+func (a *AutoEnroller) run(target Target, dryRun bool,
+	approved []int) (*bsc.EnrollmentReport, error) {
+	if target.SwapFor != 0 {
+		// Swap checks that its confirmation page lists the added class and nothing other than
+		// the dropped class and the related components, which are the classes approved for it.
+		return a.enroller.Swap(a.config.Term, target.SwapFor, target.ClassNumber, target.Options,
+			dryRun)
+	}
+	return a.enroller.Enroll(a.config.Term, approved, dryRun)
+}
+
+// approvedClasses returns the class numbers listed in a dry run.
+func approvedClasses(dryRun *bsc.EnrollmentReport) []int {
+	var res []int
+	for _, result := range dryRun.Results {
+		res = append(res, result.ClassNumber)
+	}
+	return res
+}
+
+// record adds an entry to the audit trail. The caller must hold a.lock.
+func (a *AutoEnroller) record(entry AuditEntry) {
+	entry.Time = time.Now()
+	a.audit = append(a.audit, entry)
+	if a.config.AuditLog != nil {
+		// The audit log is best-effort; the in-memory trail is always complete.
+		json.NewEncoder(a.config.AuditLog).Encode(entry)
+	}
+}
+
+func auditEntry(stage AuditStage, target Target, report *bsc.EnrollmentReport,
+	err error) AuditEntry {
+	entry := AuditEntry{Stage: stage, ClassNumber: target.ClassNumber, SwapFor: target.SwapFor}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if report != nil {
+		for _, result := range report.Results {
+			if result.Message != "" {
+				entry.Messages = append(entry.Messages, result.Message)
+			} else {
+				entry.Messages = append(entry.Messages, result.Class)
+			}
+		}
+		entry.Succeeded = report.Succeeded()
+	}
+	return entry
+}
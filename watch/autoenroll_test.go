@@ -0,0 +1,153 @@
+package watch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/unixpickle/better-student-center/bsc"
+)
+
+type fakeEnroller struct {
+	submitted []string
+	enrolled  [][]int
+	outcome   bsc.EnrollmentOutcome
+
+	// extra is a class number which dry runs list along with the requested class, if it is not 0.
+	extra int
+}
+
+func (f *fakeEnroller) Enroll(term bsc.Term, classNumbers []int,
+	dryRun bool) (*bsc.EnrollmentReport, error) {
+	if !dryRun {
+		f.enrolled = append(f.enrolled, classNumbers)
+	}
+	return f.report(classNumbers[0], dryRun, "enroll"), nil
+}
+
+func (f *fakeEnroller) Swap(term bsc.Term, dropClassNumber, addClassNumber int,
+	options bsc.CartOptions, dryRun bool) (*bsc.EnrollmentReport, error) {
+	return f.report(addClassNumber, dryRun, "swap"), nil
+}
+
+func (f *fakeEnroller) report(classNumber int, dryRun bool, action string) *bsc.EnrollmentReport {
+	if dryRun {
+		report := &bsc.EnrollmentReport{DryRun: true, Results: []bsc.EnrollmentResult{
+			{Class: "CS 2110", ClassNumber: classNumber, Outcome: bsc.EnrollmentOutcomePending},
+		}}
+		if f.extra != 0 {
+			report.Results = append(report.Results, bsc.EnrollmentResult{Class: "MATH 2940",
+				ClassNumber: f.extra, Outcome: bsc.EnrollmentOutcomePending})
+		}
+		return report
+	}
+	f.submitted = append(f.submitted, action)
+	return &bsc.EnrollmentReport{Results: []bsc.EnrollmentResult{
+		{Class: "CS 2110", ClassNumber: classNumber, Message: "Error: class is full.",
+			Outcome: f.outcome},
+	}}
+}
+
+func TestAutoEnroller(t *testing.T) {
+	term := bsc.Term{Description: "Fall 2015"}
+	if _, err := NewAutoEnroller(&fakeEnroller{}, AutoEnrollConfig{Term: term}); err == nil {
+		t.Error("an AutoEnroller without a policy should not be allowed")
+	}
+
+	enroller := &fakeEnroller{outcome: bsc.EnrollmentOutcomeClassClosed}
+	var log bytes.Buffer
+	auto, err := NewAutoEnroller(enroller, AutoEnrollConfig{
+		Term:        term,
+		Targets:     []Target{{ClassNumber: 1234, SwapFor: 1111}},
+		MaxAttempts: 2,
+		Policy:      AlwaysConfirm,
+		AuditLog:    &log,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opened := Event{Kind: EventOpened, Term: term, ClassNumber: 1234}
+	for i := 0; i < 3; i++ {
+		if err := auto.Notify(opened); err != nil {
+			t.Fatal(err)
+		}
+	}
+	auto.Notify(Event{Kind: EventOpened, Term: term, ClassNumber: 9999})
+	auto.Notify(Event{Kind: EventClosed, Term: term, ClassNumber: 1234})
+
+	if len(enroller.submitted) != 2 || enroller.submitted[0] != "swap" {
+		t.Error("unexpected submissions:", enroller.submitted)
+	}
+	audit := auto.Audit()
+	expected := []AuditStage{AuditDryRun, AuditSubmitted, AuditDryRun, AuditSubmitted,
+		AuditLimitReached}
+	if len(audit) != len(expected) {
+		t.Fatal("expected", len(expected), "audit entries but got", len(audit))
+	}
+	for i, stage := range expected {
+		if audit[i].Stage != stage {
+			t.Error("audit entry", i, "should be", stage, "but got", audit[i].Stage)
+		}
+	}
+	if audit[1].Succeeded || audit[1].Messages[0] != "Error: class is full." ||
+		audit[1].SwapFor != 1111 {
+		t.Error("unexpected submission entry:", audit[1])
+	}
+	if strings.Count(log.String(), "\n") != len(expected) {
+		t.Error("audit log should have one line per entry:", log.String())
+	}
+
+	declined := &fakeEnroller{outcome: bsc.EnrollmentOutcomeSuccess}
+	auto, _ = NewAutoEnroller(declined, AutoEnrollConfig{
+		Term:    term,
+		Targets: []Target{{ClassNumber: 1234}},
+		Policy: ConfirmFunc(func(Target, *bsc.EnrollmentReport) bool {
+			return false
+		}),
+	})
+	auto.Notify(opened)
+	if len(declined.submitted) != 0 || auto.Audit()[1].Stage != AuditDeclined {
+		t.Error("declined attempts should not be submitted")
+	}
+}
+
+func TestAutoEnrollerRejectsExtraClasses(t *testing.T) {
+	term := bsc.Term{Description: "Fall 2015"}
+	opened := Event{Kind: EventOpened, Term: term, ClassNumber: 1234}
+	for _, policy := range []ConfirmationPolicy{AlwaysConfirm,
+		ConfirmFunc(func(Target, *bsc.EnrollmentReport) bool { return true })} {
+		enroller := &fakeEnroller{outcome: bsc.EnrollmentOutcomeSuccess, extra: 2001}
+		auto, err := NewAutoEnroller(enroller, AutoEnrollConfig{
+			Term:    term,
+			Targets: []Target{{ClassNumber: 1234}},
+			Policy:  policy,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := auto.Notify(opened); err == nil {
+			t.Error("a dry run with an extra class should fail")
+		}
+		if len(enroller.submitted) != 0 {
+			t.Error("a dry run with an extra class should not be submitted")
+		}
+		if audit := auto.Audit(); audit[1].Stage != AuditDeclined || audit[1].Error == "" {
+			t.Error("unexpected audit entry:", audit[1])
+		}
+	}
+
+	enroller := &fakeEnroller{outcome: bsc.EnrollmentOutcomeSuccess}
+	auto, _ := NewAutoEnroller(enroller, AutoEnrollConfig{
+		Term:    term,
+		Targets: []Target{{ClassNumber: 1234}},
+		Policy:  AlwaysConfirm,
+	})
+	if err := auto.Notify(opened); err != nil {
+		t.Fatal(err)
+	}
+	if len(enroller.enrolled) != 1 || len(enroller.enrolled[0]) != 1 ||
+		enroller.enrolled[0][0] != 1234 {
+		t.Error("the submission should only include the approved class:", enroller.enrolled)
+	}
+}
@@ -0,0 +1,100 @@
+package watch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/unixpickle/better-student-center/bsc"
+)
+
+// A SessionPool shares one bsc.Client between all the watchers for an account, so that an account
+// only has one Student Center session no matter how many classes are being watched.
+type SessionPool struct {
+	lock        sync.Mutex
+	sessions    map[account]*Session
+	minInterval time.Duration
+}
+
+// An account identifies a Session. Engines are identified by their root URLs rather than compared
+// directly, since an engine's type may not be comparable.
+type account struct {
+	username string
+	password string
+	rootURL  string
+}
+
+// NewSessionPool creates a SessionPool. Every Client in the pool will wait at least minInterval
+// between requests.
+func NewSessionPool(minInterval time.Duration) *SessionPool {
+	return &SessionPool{sessions: map[account]*Session{}, minInterval: minInterval}
+}
+
+// Session returns the Session for an account, creating it if necessary. Accounts with the same
+// username and password share a Session if their engines have the same RootURL.
+//
+// The Session's Client is not authenticated until it makes its first request. If the Student
+// Center session expires, the Client re-authenticates automatically.
+func (p *SessionPool) Session(username, password string, engine bsc.UniversityEngine) *Session {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	key := account{username, password, engine.RootURL()}
+	if session, ok := p.sessions[key]; ok {
+		return session
+	}
+	client := bsc.NewClient(username, password, engine)
+	client.SetMinRequestInterval(p.minInterval)
+	session := &Session{client: client}
+	p.sessions[key] = session
+	return session
+}
+
+// A Session is a bsc.Client which may be used by several goroutines.
+//
+// PeopleSoft keeps the state of each page on the server, so two concurrent operations on the same
+// page would interfere with each other. A Session prevents this by running one operation at a time.
+type Session struct {
+	lock   sync.Mutex
+	client *bsc.Client
+}
+
+// Do runs a function with exclusive use of the Session's Client.
+func (s *Session) Do(f func(c *bsc.Client) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return f(s.client)
+}
+
+// FetchClassDetail calls FetchClassDetail on the Session's Client.
+func (s *Session) FetchClassDetail(term bsc.Term, classNumber int) (*bsc.Component, error) {
+	var res *bsc.Component
+	err := s.Do(func(c *bsc.Client) error {
+		var err error
+		res, err = c.FetchClassDetail(term, classNumber)
+		return err
+	})
+	return res, err
+}
+
+// Enroll calls Enroll on the Session's Client.
+func (s *Session) Enroll(term bsc.Term, classNumbers []int,
+	dryRun bool) (*bsc.EnrollmentReport, error) {
+	var res *bsc.EnrollmentReport
+	err := s.Do(func(c *bsc.Client) error {
+		var err error
+		res, err = c.Enroll(term, classNumbers, dryRun)
+		return err
+	})
+	return res, err
+}
+
+// Swap calls Swap on the Session's Client.
+func (s *Session) Swap(term bsc.Term, dropClassNumber, addClassNumber int,
+	options bsc.CartOptions, dryRun bool) (*bsc.EnrollmentReport, error) {
+	var res *bsc.EnrollmentReport
+	err := s.Do(func(c *bsc.Client) error {
+		var err error
+		res, err = c.Swap(term, dropClassNumber, addClassNumber, options, dryRun)
+		return err
+	})
+	return res, err
+}
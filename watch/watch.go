@@ -0,0 +1,320 @@
+// Package watch polls the availability of classes and reports when seats open up.
+package watch
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/unixpickle/better-student-center/bsc"
+)
+
+// MinInterval is the shortest interval at which a Watcher polls. Shorter intervals in a Config are
+// raised to MinInterval so that watchers do not overload the Student Center.
+const MinInterval = 30 * time.Second
+
+// An EventKind is the kind of change reported by an Event.
+type EventKind int
+
+const (
+	// EventOpened means that a closed class has opened.
+	EventOpened EventKind = iota
+
+	// EventClosed means that an open class has closed.
+	EventClosed
+
+	// EventWaitlistOpened means that a full wait list has room.
+	EventWaitlistOpened
+
+	// EventCapacityIncreased means that the class's capacity has increased.
+	EventCapacityIncreased
+
+	// EventError means that a class could not be refreshed or a notifier failed. The Event's Err
+	// is set.
+	EventError
+)
+
+// String returns a human-readable version of the EventKind.
+func (k EventKind) String() string {
+	names := map[EventKind]string{
+		EventOpened:            "Opened",
+		EventClosed:            "Closed",
+		EventWaitlistOpened:    "Wait List Opened",
+		EventCapacityIncreased: "Capacity Increased",
+		EventError:             "Error",
+	}
+	if name, ok := names[k]; ok {
+		return name
+	} else {
+		return "Unknown"
+	}
+}
+
+// An Event reports a change in the availability of a class.
+type Event struct {
+	Kind        EventKind
+	Time        time.Time
+	Term        bsc.Term
+	ClassNumber int
+
+	// Previous and Current are the class's state before and after the change. Previous is nil for
+	// EventError, and Current is nil if the class could not be refreshed.
+	Previous *bsc.Component
+	Current  *bsc.Component
+
+	Err error
+}
+
+// String returns a human-readable description of the event.
+func (e Event) String() string {
+	if e.Kind == EventError {
+		return fmt.Sprintf("class %d: %v", e.ClassNumber, e.Err)
+	}
+	return fmt.Sprintf("class %d: %s", e.ClassNumber, e.Kind)
+}
+
+// A Notifier is told about every change which a Watcher detects. Notifiers are not told about
+// errors.
+type Notifier interface {
+	Notify(e Event) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(e Event) error
+
+// Notify calls f(e).
+func (f NotifierFunc) Notify(e Event) error {
+	return f(e)
+}
+
+// WriterNotifier writes a line for each event to an io.Writer, such as a log file.
+type WriterNotifier struct {
+	W io.Writer
+}
+
+// Notify writes the event and its time.
+func (w WriterNotifier) Notify(e Event) error {
+	_, err := fmt.Fprintln(w.W, e.Time.Format(time.RFC3339), e)
+	return err
+}
+
+// A Fetcher looks up the current state of a class. Both *bsc.Client and *Session are Fetchers.
+type Fetcher interface {
+	FetchClassDetail(term bsc.Term, classNumber int) (*bsc.Component, error)
+}
+
+// Config configures a Watcher.
+type Config struct {
+	Term         bsc.Term
+	ClassNumbers []int
+
+	// Interval is the time between polls. It is raised to MinInterval if it is shorter.
+	Interval time.Duration
+
+	Notifiers []Notifier
+}
+
+// A Watcher periodically refreshes the availability of a list of classes and reports changes.
+type Watcher struct {
+	fetcher Fetcher
+	config  Config
+	events  chan Event
+
+	lock    sync.Mutex
+	started bool
+	done    chan struct{}
+
+	// pollLock is held while polling, so that Poll and the background loop do not run at the same
+	// time and so that Stop does not close the events channel while a poll is sending to it.
+	pollLock sync.Mutex
+	previous map[int]*bsc.Component
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWatcher creates a Watcher. It does not poll until Start or Poll is called.
+func NewWatcher(fetcher Fetcher, config Config) *Watcher {
+	if config.Interval < MinInterval {
+		config.Interval = MinInterval
+	}
+	return &Watcher{
+		fetcher:  fetcher,
+		config:   config,
+		events:   make(chan Event, len(config.ClassNumbers)*4),
+		previous: map[int]*bsc.Component{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Events returns the channel on which the Watcher sends events. The channel is closed by Stop.
+//
+// The channel must be drained, since the Watcher blocks when the channel is full.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Start polls in the background until Stop is called. The first poll happens immediately.
+// Calling Start more than once, or after Stop, has no effect.
+func (w *Watcher) Start() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.started || w.stopped() {
+		return
+	}
+	w.started = true
+	go w.run()
+}
+
+// Stop stops polling and closes the events channel. A Watcher cannot be restarted after it is
+// stopped. It is safe to call Stop more than once, and to call it while Poll is running.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		w.lock.Lock()
+		close(w.stop)
+		started := w.started
+		w.lock.Unlock()
+		if started {
+			<-w.done
+		}
+
+		w.pollLock.Lock()
+		close(w.events)
+		w.pollLock.Unlock()
+	})
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		if !w.lockedPoll() {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// Poll refreshes every class once and reports any changes. Polls never overlap, so Poll waits for
+// any poll that is already running.
+//
+// Poll blocks while the events channel is full, and returns early if Stop is called. It does
+// nothing once the Watcher is stopped.
+//
+// The first time a class is refreshed, no changes are reported, since there is nothing to compare
+// it to.
+func (w *Watcher) Poll() {
+	w.lockedPoll()
+}
+
+func (w *Watcher) stopped() bool {
+	select {
+	case <-w.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// lockedPoll polls while holding pollLock and returns false if the Watcher was stopped.
+func (w *Watcher) lockedPoll() bool {
+	w.pollLock.Lock()
+	defer w.pollLock.Unlock()
+	if w.stopped() {
+		return false
+	}
+	return w.poll(w.stop)
+}
+
+// poll refreshes every class and returns false if it was stopped.
+func (w *Watcher) poll(stop chan struct{}) bool {
+	for _, classNumber := range w.config.ClassNumbers {
+		current, err := w.fetcher.FetchClassDetail(w.config.Term, classNumber)
+		now := time.Now()
+		if err != nil {
+			if !w.send(Event{Kind: EventError, Time: now, Term: w.config.Term,
+				ClassNumber: classNumber, Err: err}, stop) {
+				return false
+			}
+			continue
+		}
+
+		previous := w.previous[classNumber]
+		w.previous[classNumber] = current
+		if previous == nil {
+			continue
+		}
+		for _, kind := range Transitions(previous, current) {
+			event := Event{Kind: kind, Time: now, Term: w.config.Term, ClassNumber: classNumber,
+				Previous: previous, Current: current}
+			if !w.send(event, stop) {
+				return false
+			}
+			for _, notifier := range w.config.Notifiers {
+				if err := notifier.Notify(event); err != nil {
+					errEvent := Event{Kind: EventError, Time: now, Term: w.config.Term,
+						ClassNumber: classNumber, Current: current, Err: err}
+					if !w.send(errEvent, stop) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (w *Watcher) send(e Event, stop chan struct{}) bool {
+	select {
+	case w.events <- e:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// Transitions compares two states of a class and returns the changes between them.
+//
+// A class is considered open if its Open field says so or, if that is unknown, if it has available
+// seats.
+func Transitions(previous, current *bsc.Component) []EventKind {
+	var res []EventKind
+	wasOpen, isOpen := componentOpen(previous), componentOpen(current)
+	if !wasOpen && isOpen {
+		res = append(res, EventOpened)
+	} else if wasOpen && !isOpen {
+		res = append(res, EventClosed)
+	}
+
+	prevAvail, curAvail := previous.ClassAvailability, current.ClassAvailability
+	if prevAvail == nil || curAvail == nil {
+		return res
+	}
+	if waitlistFull(prevAvail) && !waitlistFull(curAvail) {
+		res = append(res, EventWaitlistOpened)
+	}
+	if curAvail.Capacity > prevAvail.Capacity {
+		res = append(res, EventCapacityIncreased)
+	}
+	return res
+}
+
+func componentOpen(c *bsc.Component) bool {
+	if c.Open != nil {
+		return *c.Open
+	}
+	return c.ClassAvailability != nil && c.ClassAvailability.AvailableSeats > 0
+}
+
+// waitlistFull returns true if a class has no room on its wait list. Classes without wait lists
+// are always considered full.
+func waitlistFull(a *bsc.ClassAvailability) bool {
+	return a.WaitListTotal >= a.WaitListCapacity
+}
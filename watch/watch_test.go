@@ -0,0 +1,130 @@
+package watch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/unixpickle/better-student-center/bsc"
+)
+
+type fakeFetcher struct {
+	states map[int][]*bsc.Component
+}
+
+func (f *fakeFetcher) FetchClassDetail(term bsc.Term, classNumber int) (*bsc.Component, error) {
+	states := f.states[classNumber]
+	if len(states) == 0 {
+		return nil, errors.New("class not found")
+	}
+	state := states[0]
+	if len(states) > 1 {
+		f.states[classNumber] = states[1:]
+	}
+	return state, nil
+}
+
+func testComponent(open bool, capacity, waitListTotal int) *bsc.Component {
+	return &bsc.Component{
+		Open: &open,
+		ClassAvailability: &bsc.ClassAvailability{
+			Capacity:         capacity,
+			WaitListCapacity: 10,
+			WaitListTotal:    waitListTotal,
+		},
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	fetcher := &fakeFetcher{states: map[int][]*bsc.Component{
+		1234: {testComponent(false, 100, 10), testComponent(true, 110, 9)},
+		2001: {testComponent(true, 50, 0)},
+	}}
+	var notified []Event
+	watcher := NewWatcher(fetcher, Config{
+		ClassNumbers: []int{1234, 2001, 9999},
+		Notifiers: []Notifier{NotifierFunc(func(e Event) error {
+			notified = append(notified, e)
+			return nil
+		})},
+	})
+
+	watcher.Poll()
+	if e := <-watcher.Events(); e.Kind != EventError || e.ClassNumber != 9999 {
+		t.Error("expected an error for the unknown class but got", e)
+	}
+	if len(watcher.Events()) != 0 || len(notified) != 0 {
+		t.Fatal("the first poll should not report any changes")
+	}
+
+	watcher.Poll()
+	expected := []EventKind{EventOpened, EventWaitlistOpened, EventCapacityIncreased, EventError}
+	for _, kind := range expected {
+		if e := <-watcher.Events(); e.Kind != kind {
+			t.Error("expected", kind, "but got", e)
+		}
+	}
+	if len(notified) != 3 || notified[0].ClassNumber != 1234 {
+		t.Error("unexpected notifications:", notified)
+	}
+
+	watcher.Stop()
+	if _, ok := <-watcher.Events(); ok {
+		t.Error("events channel should be closed")
+	}
+}
+
+func TestWatcherStopDuringPoll(t *testing.T) {
+	fetcher := &fakeFetcher{states: map[int][]*bsc.Component{}}
+	watcher := NewWatcher(fetcher, Config{ClassNumbers: []int{9999}})
+
+	// Every poll sends an error, so the events channel fills up and Poll blocks.
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for i := 0; i < 10; i++ {
+			watcher.Poll()
+		}
+	}()
+	for len(watcher.Events()) < cap(watcher.Events()) {
+		time.Sleep(time.Millisecond)
+	}
+
+	watcher.Stop()
+	watcher.Stop()
+	select {
+	case <-polled:
+	case <-time.After(time.Second):
+		t.Fatal("Poll should return once the watcher is stopped")
+	}
+	watcher.Poll()
+	watcher.Start()
+}
+
+func TestSessionPool(t *testing.T) {
+	pool := NewSessionPool(MinInterval)
+	s1 := pool.Session("user", "pass", bsc.CornellEngine{})
+	s2 := pool.Session("user", "pass", bsc.CornellEngine{})
+	s3 := pool.Session("other", "pass", bsc.CornellEngine{})
+	if s1 != s2 {
+		t.Error("sessions for the same account should be shared")
+	}
+	if s1 == s3 {
+		t.Error("sessions for different accounts should not be shared")
+	}
+	if s1 == pool.Session("user", "pass", bsc.URIEngine{}) {
+		t.Error("sessions for different universities should not be shared")
+	}
+
+	// Engines which are not comparable must not cause a panic.
+	engine := mapEngine{bsc.CornellEngine{}, map[string]string{}}
+	if pool.Session("user", "pass", engine) != s1 {
+		t.Error("engines with the same root URL should share sessions")
+	}
+}
+
+// mapEngine is a bsc.UniversityEngine whose type is not comparable.
+type mapEngine struct {
+	bsc.CornellEngine
+	settings map[string]string
+}